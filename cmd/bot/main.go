@@ -2,8 +2,14 @@
 package main
 
 import (
-	"github.com/joho/godotenv"
+	"context"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
 	"telegram-anonymous-bot/internal/bot"
 	"telegram-anonymous-bot/internal/config"
 	"telegram-anonymous-bot/internal/storage"
@@ -19,6 +25,10 @@ func main() {
 	if err != nil {
 		log.Fatal("Error loading config:", err)
 	}
+	if err := logger.Setup(os.Stdout, cfg.LogLevel, cfg.LogFormat); err != nil {
+		log.Fatal(err)
+	}
+	logger.SetSalt(cfg.LogIDSalt)
 
 	// Инициализация SQLite хранилища
 	store, err := storage.NewSQLiteStorage(cfg.DatabaseURL)
@@ -32,8 +42,22 @@ func main() {
 		log.Fatal("Error initializing Telegram bot:", err)
 	}
 
-	// Запуск бота
-	telegramBot.Start()
+	// Запуск бота до SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runErr := telegramBot.Start(ctx)
+	slog.Info("Остановка бота...")
+
+	// Даём фоновым задачам завершиться и только потом закрываем базу
+	telegramBot.Shutdown(cfg.ShutdownTimeout)
+	if err := store.Close(); err != nil {
+		slog.Error("Ошибка закрытия базы", "error", err)
+	}
+
+	if runErr != nil {
+		log.Fatal("Error running Telegram bot:", runErr)
+	}
 }
 
 func LoadEnv() {
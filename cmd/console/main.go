@@ -0,0 +1,79 @@
+// cmd/console/main.go
+//
+// console запускает бота в терминале без Telegram: строки из stdin — сообщения от вымышленного
+// пользователя, ответы бота печатаются в stdout. Справка по служебным командам — :help.
+//
+//	go run ./cmd/console [-db console.db] [-user 1000] [-admin 999] [-llm fake]
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"telegram-anonymous-bot/internal/bot"
+	"telegram-anonymous-bot/internal/config"
+	"telegram-anonymous-bot/internal/console"
+	"telegram-anonymous-bot/internal/storage"
+	"telegram-anonymous-bot/pkg/logger"
+)
+
+func main() {
+	dbPath := flag.String("db", "console.db", "база SQLite для консоли")
+	userID := flag.Int64("user", 1000, "ID пользователя по умолчанию (:as user)")
+	adminID := flag.Int64("admin", 0, "ID администратора (:as admin); 0 — ADMIN_ID из конфигурации")
+	provider := flag.String("llm", "", "LLM_PROVIDER для консоли (пусто — из конфигурации)")
+	flag.Parse()
+
+	logger.Init()
+	_ = godotenv.Load()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal("Error loading config:", err)
+	}
+	// Журнал — в stderr, чтобы не перемешивался с диалогом
+	if err := logger.Setup(os.Stderr, cfg.LogLevel, cfg.LogFormat); err != nil {
+		log.Fatal(err)
+	}
+	logger.SetSalt(cfg.LogIDSalt)
+	if *provider != "" {
+		cfg.LLMProvider = *provider
+	}
+	if *adminID != 0 {
+		cfg.AdminID = int(*adminID)
+	}
+	if cfg.AdminID == 0 {
+		cfg.AdminID = 999
+	}
+
+	store, err := storage.NewSQLiteStorage(*dbPath)
+	if err != nil {
+		log.Fatal("Error initializing SQLite storage:", err)
+	}
+
+	bc, err := bot.NewBotCore(cfg, store)
+	if err != nil {
+		log.Fatal("Error initializing bot:", err)
+	}
+	messenger := console.NewMessenger(os.Stdout)
+	bc.Messenger = messenger
+	telegramBot := bot.NewTelegramBotWithCore(bc, nil)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runErr := console.New(telegramBot, messenger, os.Stdout, *userID, int64(cfg.AdminID)).Run(ctx, os.Stdin)
+
+	telegramBot.Shutdown(cfg.ShutdownTimeout)
+	if err := store.Close(); err != nil {
+		slog.Error("Ошибка закрытия базы", "error", err)
+	}
+	if runErr != nil {
+		log.Fatal("Error reading console input:", runErr)
+	}
+}
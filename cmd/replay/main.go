@@ -0,0 +1,100 @@
+// cmd/replay/main.go
+//
+// replay прогоняет журнал обновлений (UPDATES_LOG) через обработчики бота без Telegram:
+// запросы, которые бот отправил бы, печатаются в stdout.
+//
+//	go run ./cmd/replay [-db bot.db] [-llm fake] [-workers 1] updates.jsonl
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"telegram-anonymous-bot/internal/bot"
+	"telegram-anonymous-bot/internal/bot/telegramtest"
+	"telegram-anonymous-bot/internal/config"
+	"telegram-anonymous-bot/internal/storage"
+	"telegram-anonymous-bot/pkg/logger"
+)
+
+func main() {
+	dbPath := flag.String("db", "", "база SQLite, к которой применяются обновления (пусто — новая временная база)")
+	provider := flag.String("llm", "fake", "LLM_PROVIDER для прогона (пусто — из конфигурации)")
+	workers := flag.Int("workers", 1, "сколько обновлений обрабатывать параллельно (1 — строго по порядку)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Использование: %s [флаги] <файл.jsonl | ->\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger.Init()
+	_ = godotenv.Load()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal("Error loading config:", err)
+	}
+	// stdout занят запросами бота, журнал — в stderr
+	if err := logger.Setup(os.Stderr, cfg.LogLevel, cfg.LogFormat); err != nil {
+		log.Fatal(err)
+	}
+	logger.SetSalt(cfg.LogIDSalt)
+	if *provider != "" {
+		cfg.LLMProvider = *provider
+	}
+	cfg.Workers = *workers
+	cfg.DigestTime = ""
+
+	// По умолчанию — чистая база: прогон не должен менять рабочие данные
+	if *dbPath == "" {
+		dir, err := os.MkdirTemp("", "replay")
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		*dbPath = filepath.Join(dir, "replay.db")
+	}
+	store, err := storage.NewSQLiteStorage(*dbPath)
+	if err != nil {
+		log.Fatal("Error initializing SQLite storage:", err)
+	}
+	defer store.Close()
+
+	bc, err := bot.NewBotCore(cfg, store)
+	if err != nil {
+		log.Fatal("Error initializing bot:", err)
+	}
+	// Вместо Telegram — запись запросов с печатью каждого
+	bc.Messenger = &telegramtest.Recorder{Hook: func(c telegramtest.Call) error {
+		fmt.Printf("%s → %d: %s\n", c.Method, c.ChatID, strings.ReplaceAll(c.Text, "\n", `\n`))
+		return nil
+	}}
+	telegramBot := bot.NewTelegramBotWithCore(bc, nil)
+
+	var in io.Reader = os.Stdin
+	if name := flag.Arg(0); name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	n, err := telegramBot.Replay(in)
+	telegramBot.Shutdown(cfg.ShutdownTimeout)
+	if err != nil {
+		log.Fatalf("Ошибка чтения журнала после %d обновлений: %v", n, err)
+	}
+	slog.Info("Журнал прогнан", "updates", n)
+}
@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+)
+
+// staffChatsKey — ключ bot_state со списком администраторов, которым опубликовано их меню.
+const staffChatsKey = "staff_command_chats"
+
+// PublishCommands публикует меню команд в Telegram из реестра: общее меню пользователей —
+// на каждом языке каталога, меню администратора — в личном чате каждого администратора на его
+// языке. Тем, кто перестал быть администратором, возвращается общее меню. Вызывается при
+// запуске, поэтому изменения ADMIN_ID/ADMIN_IDS применяются после перезапуска.
+func (t *TelegramBot) PublishCommands(ctx context.Context) error {
+	bc := t.core
+	var errs []error
+	request := func(c tgbotapi.Chattable) {
+		if _, err := bc.Messenger.Request(c); err != nil {
+			errs = append(errs, core.WithoutURL(err))
+		}
+	}
+
+	scope := tgbotapi.NewBotCommandScopeDefault()
+	request(tgbotapi.NewSetMyCommandsWithScope(scope, t.commands.Menu(bc.DefaultLang(), core.RoleUser)...))
+	for _, lang := range bc.Catalog().Languages() {
+		request(tgbotapi.NewSetMyCommandsWithScopeAndLanguage(scope, lang, t.commands.Menu(lang, core.RoleUser)...))
+	}
+
+	admins := bc.AdminIDs()
+	for _, id := range admins {
+		request(t.commands.AdminMenu(id, bc.UserLang(ctx, id, "")))
+	}
+
+	previous, err := bc.Storage.GetState(staffChatsKey)
+	if err != nil {
+		return err
+	}
+	for _, field := range strings.Split(previous, ",") {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err == nil && !slices.Contains(admins, id) {
+			request(tgbotapi.NewDeleteMyCommandsWithScope(tgbotapi.NewBotCommandScopeChat(id)))
+		}
+	}
+
+	ids := make([]string, 0, len(admins))
+	for _, id := range admins {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	if err := bc.Storage.SetState(staffChatsKey, strings.Join(ids, ",")); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("ошибка публикации меню команд: %w", err)
+	}
+	return nil
+}
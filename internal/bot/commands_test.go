@@ -0,0 +1,102 @@
+package bot_test
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/telegramtest"
+)
+
+// menus раскладывает вызовы setMyCommands: меню администраторов по чату, остальные — по языку.
+func menus(calls []telegramtest.Call) (languages map[string][]tgbotapi.BotCommand, admins map[int64][]tgbotapi.BotCommand) {
+	languages = make(map[string][]tgbotapi.BotCommand)
+	admins = make(map[int64][]tgbotapi.BotCommand)
+	for _, call := range calls {
+		cfg := call.Config.(tgbotapi.SetMyCommandsConfig)
+		if call.ChatID != 0 {
+			admins[call.ChatID] = cfg.Commands
+		} else {
+			languages[cfg.LanguageCode] = cfg.Commands
+		}
+	}
+	return languages, admins
+}
+
+// hasCommand сообщает, есть ли в меню команда name.
+func hasCommand(menu []tgbotapi.BotCommand, name string) bool {
+	for _, c := range menu {
+		if c.Command == name {
+			return true
+		}
+	}
+	return false
+}
+
+// description возвращает описание команды name в меню.
+func description(menu []tgbotapi.BotCommand, name string) string {
+	for _, c := range menu {
+		if c.Command == name {
+			return c.Description
+		}
+	}
+	return ""
+}
+
+func TestPublishCommands(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetState", "staff_command_chats").Return("999999,777", nil)
+	storageMock.On("SetState", "staff_command_chats", "999999,555").Return(nil)
+	telegramBot, tg := newTestBot(storageMock)
+	telegramBot.Core().Config.AdminIDs = []int{555}
+
+	if err := telegramBot.PublishCommands(context.Background()); err != nil {
+		t.Fatalf("PublishCommands failed: %v", err)
+	}
+
+	calls := tg.Calls("setMyCommands")
+	// Общее меню без языка и по одному на каждый язык каталога, плюс меню двух администраторов
+	if len(calls) != 1+len(telegramBot.Core().Catalog().Languages())+2 {
+		t.Fatalf("Unexpected setMyCommands calls: %v", calls)
+	}
+	languages, admins := menus(calls)
+
+	if user := languages["en"]; !hasCommand(user, "start") || hasCommand(user, "answer") {
+		t.Errorf("User menu must contain only user commands, got %v", user)
+	}
+	if description(languages["en"], "start") != "get started" || description(languages[""], "start") != "начало работы" {
+		t.Errorf("Expected localized descriptions, got %v", languages)
+	}
+	for _, id := range []int64{999999, 555} {
+		if menu := admins[id]; !hasCommand(menu, "answer") || !hasCommand(menu, "start") {
+			t.Errorf("Admin %d must get the full menu, got %v", id, menu)
+		}
+	}
+
+	// Бывший администратор возвращается к общему меню
+	if deleted := tg.Calls("deleteMyCommands"); len(deleted) != 1 || deleted[0].ChatID != 777 {
+		t.Errorf("Expected the menu of the former admin to be removed, got %v", deleted)
+	}
+	storageMock.AssertCalled(t, "SetState", "staff_command_chats", "999999,555")
+}
+
+func TestLanguageChangeRefreshesAdminMenu(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("SetUserLanguage", 999999, "en").Return(nil)
+	storageMock.On("SetUserLanguage", 12345, "en").Return(nil)
+	telegramBot, tg := newTestBot(storageMock)
+
+	for _, id := range []int64{12345, 999999} {
+		telegramBot.HandleMessage(&tgbotapi.Message{
+			Text:     "/language en",
+			From:     &tgbotapi.User{ID: id},
+			Chat:     &tgbotapi.Chat{ID: id},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 9}},
+		})
+	}
+
+	calls := tg.Calls("setMyCommands")
+	if len(calls) != 1 || calls[0].ChatID != 999999 {
+		t.Fatalf("Expected only the admin menu to be refreshed, got %v", calls)
+	}
+}
@@ -1,38 +1,228 @@
 package core
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
+	"sync"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"telegram-anonymous-bot/internal/config"
+	"telegram-anonymous-bot/internal/crisis"
+	"telegram-anonymous-bot/internal/filter"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/llm"
+	"telegram-anonymous-bot/internal/metrics"
+	"telegram-anonymous-bot/internal/models"
 	"telegram-anonymous-bot/internal/storage"
+	"telegram-anonymous-bot/internal/translate"
+	"telegram-anonymous-bot/pkg/logger"
 )
 
+var errLLMNotConfigured = errors.New("нейросеть не настроена")
+
 type BotCore struct {
-	BotAPI  *tgbotapi.BotAPI
-	Config  *config.Config
-	Storage storage.Storage
+	// Messenger отправляет сообщения и запросы в Telegram; в тестах — telegramtest.Recorder.
+	Messenger Messenger
+	Config    *config.Config
+	Storage   storage.Storage
+	// HTTPClient — клиент для внешних API (учитывает PROXY_URL).
+	HTTPClient *http.Client
+	LLM        llm.Provider
+	// Filter — локальные правила проверки вопросов; nil — фильтр выключен.
+	Filter *filter.Filter
+	// Crisis — детектор кризисных сообщений; nil — выключен.
+	Crisis *crisis.Detector
+	// Translator — перевод вопросов и ответов; nil — выключен.
+	Translator translate.Translator
+	// I18n — каталог текстов; nil — встроенный i18n.Default().
+	I18n *i18n.Catalog
+	// Metrics — метрики для /metrics; nil — не собираются.
+	Metrics *metrics.Set
+
+	background sync.WaitGroup
+}
+
+// AdminIDs возвращает всех администраторов: ADMIN_ID и ADMIN_IDS без повторов.
+func (bc *BotCore) AdminIDs() []int64 {
+	ids := []int64{int64(bc.Config.AdminID)}
+	for _, id := range bc.Config.AdminIDs {
+		if !slices.Contains(ids, int64(id)) {
+			ids = append(ids, int64(id))
+		}
+	}
+	return ids
+}
+
+// IsAdmin проверяет, является ли пользователь администратором.
+func (bc *BotCore) IsAdmin(userID int64) bool {
+	return slices.Contains(bc.AdminIDs(), userID)
+}
+
+// Role — роль пользователя, определяющая доступные команды.
+type Role int
+
+const (
+	RoleUser Role = iota
+	RoleAdmin
+)
+
+// Role возвращает роль пользователя.
+func (bc *BotCore) Role(userID int64) Role {
+	if bc.IsAdmin(userID) {
+		return RoleAdmin
+	}
+	return RoleUser
+}
+
+// NotifyAdmins отправляет всем администраторам текст по ключу, каждому на его языке.
+func (bc *BotCore) NotifyAdmins(ctx context.Context, key string, params ...i18n.Params) {
+	for _, id := range bc.AdminIDs() {
+		bc.SendMessage(ctx, id, bc.T(bc.UserLang(ctx, id, ""), key, params...))
+	}
+}
+
+// Go запускает фоновую задачу, не задерживающую обработку обновления.
+func (bc *BotCore) Go(task func()) {
+	bc.background.Add(1)
+	go func() {
+		defer bc.background.Done()
+		task()
+	}()
+}
+
+// Wait дожидается завершения фоновых задач, запущенных через Go.
+func (bc *BotCore) Wait() {
+	bc.background.Wait()
+}
+
+// WaitTimeout дожидается фоновых задач не дольше timeout; false — не все успели завершиться.
+func (bc *BotCore) WaitTimeout(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		bc.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
 }
 
 // SendMessage отправляет обычное сообщение пользователю.
-func (bc *BotCore) SendMessage(chatID int64, text string) {
-	msg := tgbotapi.NewMessage(chatID, text)
-	if _, err := bc.BotAPI.Send(msg); err != nil {
-		log.Printf("SendMessage error: %v", err)
+func (bc *BotCore) SendMessage(ctx context.Context, chatID int64, text string) {
+	bc.Send(ctx, tgbotapi.NewMessage(chatID, text))
+}
+
+// Send отправляет подготовленное сообщение (с клавиатурой, медиа и т.п.).
+// Ошибка Telegram пишется в журнал вместе с атрибутами обновления из ctx.
+func (bc *BotCore) Send(ctx context.Context, c tgbotapi.Chattable) {
+	if _, err := bc.Messenger.Send(c); err != nil {
+		LogTelegramError(ctx, c, err)
+	}
+}
+
+// Request выполняет служебный запрос к Telegram (ответ на callback, редактирование и т.п.).
+func (bc *BotCore) Request(ctx context.Context, c tgbotapi.Chattable) {
+	if _, err := bc.Messenger.Request(c); err != nil {
+		LogTelegramError(ctx, c, err)
+	}
+}
+
+// LogTelegramError пишет в журнал неудачный запрос к Telegram: тип запроса и хеш чата.
+func LogTelegramError(ctx context.Context, c tgbotapi.Chattable, err error) {
+	args := []any{"request", fmt.Sprintf("%T", c), "error", WithoutURL(err)}
+	if chatID := requestChat(c); chatID != 0 {
+		args = append(args, "to", logger.ID(chatID))
+	}
+	slog.ErrorContext(ctx, "Ошибка запроса к Telegram", args...)
+}
+
+// WithoutURL убирает из ошибки HTTP-клиента адрес запроса: в адресах Bot API токен бота.
+// Все ошибки запросов к Telegram перед записью в журнал или выдачей наружу проходят через неё.
+func WithoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
 	}
+	return err
+}
+
+// LogStorageError пишет в журнал ошибку обращения к базе: операцию op и ошибку.
+func LogStorageError(ctx context.Context, op string, err error) {
+	slog.ErrorContext(ctx, "Ошибка базы данных", "op", op, "error", err)
 }
 
-func (bc *BotCore) sendCommandsKeyboard(chatID int64) {
+// requestChat возвращает чат, которому адресован запрос (0 — неизвестен).
+func requestChat(c tgbotapi.Chattable) int64 {
+	switch c := c.(type) {
+	case tgbotapi.MessageConfig:
+		return c.ChatID
+	case tgbotapi.PhotoConfig:
+		return c.ChatID
+	case tgbotapi.VideoConfig:
+		return c.ChatID
+	case tgbotapi.EditMessageTextConfig:
+		return c.ChatID
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return c.ChatID
+	case tgbotapi.ChatActionConfig:
+		return c.ChatID
+	}
+	return 0
+}
+
+// DeliverAnswer помечает вопрос отвеченным и отправляет ответ его автору. Вопрос закрывается
+// в базе до отправки: если на него уже ответили (в том числе одновременно из другого
+// интерфейса), автор не получит второй ответ, а DeliverAnswer вернёт ErrAlreadyAnswered.
+// Если включён перевод, автор получает ответ на своём языке; в базе остаётся исходный текст.
+func (bc *BotCore) DeliverAnswer(ctx context.Context, q *models.Question, answerText string) error {
+	previous := *q
+	q.Answered = true
+	q.Answer = answerText
+	if err := bc.closeQuestion(q, previous); err != nil {
+		return err
+	}
+	bc.Metrics.QuestionAnswered(q.CreatedAt)
+
+	lang := bc.UserLang(ctx, int64(q.UserID), q.Language)
+	bc.SendMessage(ctx, int64(q.UserID), bc.T(lang, "answer.delivered", i18n.Params{
+		"id":   q.ID,
+		"text": bc.translateAnswer(ctx, q, answerText),
+	}))
+	return nil
+}
+
+// translateAnswer переводит ответ на язык автора вопроса. При ошибке перевода
+// отправляется исходный текст: лучше ответ на чужом языке, чем никакого.
+func (bc *BotCore) translateAnswer(ctx context.Context, q *models.Question, answerText string) string {
+	if bc.Translator == nil || q.Language == "" || q.Language == bc.Config.TranslateTo {
+		return answerText
+	}
+	res, err := bc.Translate(ctx, answerText, q.Language)
+	if err != nil {
+		slog.ErrorContext(ctx, "Не удалось перевести ответ", "question", q.ID, "error", err)
+		return answerText
+	}
+	if res.Text == "" {
+		return answerText
+	}
+	return res.Text
+}
+
+func (bc *BotCore) sendCommandsKeyboard(ctx context.Context, chatID int64, lang string) {
 	// Создаём кнопки
 	row := tgbotapi.NewKeyboardButtonRow(
 		tgbotapi.NewKeyboardButton("/answer "),
-		tgbotapi.NewKeyboardButton("/askcohere "),
+		tgbotapi.NewKeyboardButton("/ask "),
 	)
 	// Можно добавить больше кнопок, если нужно
 
@@ -43,80 +233,51 @@ func (bc *BotCore) sendCommandsKeyboard(chatID int64) {
 	replyKeyboard.ResizeKeyboard = true  // подгоняем размер под кнопки
 
 	// Отправляем сообщение с клавиатурой
-	msg := tgbotapi.NewMessage(chatID, "Выберите команду:")
+	msg := tgbotapi.NewMessage(chatID, bc.T(lang, "common.choose_command"))
 	msg.ReplyMarkup = replyKeyboard
-	_, _ = bc.BotAPI.Send(msg)
+	bc.Send(ctx, msg)
 }
 
-// QueryCohereWithProxy пример интеграции с Cohere.
-func (bc *BotCore) QueryCohereWithProxy(prompt string) (string, error) {
-	const apiURL = "https://api.cohere.ai/generate"
-
-	payload := struct {
-		Model       string  `json:"model"`
-		Prompt      string  `json:"prompt"`
-		MaxTokens   int     `json:"max_tokens"`
-		Temperature float64 `json:"temperature"`
-	}{
-		Model:       "command-xlarge-nightly",
-		Prompt:      prompt,
-		MaxTokens:   512,
-		Temperature: 0.7,
-	}
-	data, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("ошибка формирования JSON: %w", err)
-	}
-
-	client := bc.getHttpClientWithProxy(bc.Config.ProxyURL)
-
-	req, err := http.NewRequest(http.MethodPost, apiURL, bytes.NewBuffer(data))
-	if err != nil {
-		return "", fmt.Errorf("ошибка создания HTTP-запроса: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+bc.Config.CohereKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("ошибка отправки запроса к Cohere API: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return "", fmt.Errorf("Cohere API error: %s", string(body))
+// Generate отправляет запрос настроенной нейросети с таймаутом из конфигурации.
+func (bc *BotCore) Generate(ctx context.Context, prompt string) (string, error) {
+	if bc.LLM == nil {
+		return "", errLLMNotConfigured
 	}
+	ctx, cancel := bc.llmContext(ctx)
+	defer cancel()
+	return bc.LLM.Generate(ctx, prompt)
+}
 
-	var cohereResp struct {
-		ID   string `json:"id"`
-		Text string `json:"text"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&cohereResp); err != nil {
-		return "", fmt.Errorf("ошибка обработки JSON-ответа: %w", err)
+// Translate переводит текст на язык target с таймаутом нейросети из конфигурации.
+func (bc *BotCore) Translate(ctx context.Context, text, target string) (translate.Result, error) {
+	if bc.Translator == nil {
+		return translate.Result{}, errors.New("перевод не настроен")
 	}
+	ctx, cancel := bc.llmContext(ctx)
+	defer cancel()
+	return bc.Translator.Translate(ctx, text, target)
+}
 
-	if cohereResp.Text == "" {
-		return "", fmt.Errorf("пустой ответ от Cohere")
+func (bc *BotCore) llmContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if bc.Config.LLMTimeout > 0 {
+		return context.WithTimeout(ctx, bc.Config.LLMTimeout)
 	}
-
-	return cohereResp.Text, nil
+	return context.WithCancel(ctx)
 }
 
-// getHttpClientWithProxy возвращает http-клиент, учитывая прокси.
-func (bc *BotCore) getHttpClientWithProxy(proxyURL string) *http.Client {
+// NewHTTPClient возвращает http-клиент, учитывая прокси.
+func NewHTTPClient(proxyURL string) (*http.Client, error) {
 	if proxyURL == "" {
-		log.Println("Прокси не указан, используем стандартный клиент")
-		return http.DefaultClient
+		slog.Info("Прокси не указан, используем стандартный клиент")
+		return http.DefaultClient, nil
 	}
 	parsedURL, err := url.Parse(proxyURL)
 	if err != nil {
-		log.Fatalf("Некорректный адрес прокси: %v", err)
+		return nil, fmt.Errorf("некорректный адрес прокси: %w", err)
 	}
 	return &http.Client{
 		Transport: &http.Transport{
 			Proxy: http.ProxyURL(parsedURL),
 		},
-	}
+	}, nil
 }
@@ -0,0 +1,36 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-anonymous-bot/internal/bot/core"
+)
+
+func TestLogTelegramErrorHidesToken(t *testing.T) {
+	var out strings.Builder
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&out, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	// Так tgbotapi возвращает сетевые ошибки: адрес запроса содержит токен бота
+	const token = "123456:SECRET-token"
+	err := &url.Error{Op: "Post", URL: "https://api.telegram.org/bot" + token + "/sendMessage", Err: errors.New("connection refused")}
+
+	core.LogTelegramError(context.Background(), tgbotapi.NewMessage(1, "текст"), err)
+	if strings.Contains(out.String(), "SECRET") || !strings.Contains(out.String(), "connection refused") {
+		t.Errorf("Expected the error without the request URL, got:\n%s", out.String())
+	}
+
+	wrapped := core.WithoutURL(fmt.Errorf("ошибка setWebhook: %w", err))
+	if strings.Contains(wrapped.Error(), "SECRET") {
+		t.Errorf("Expected a wrapped error without the request URL, got %q", wrapped)
+	}
+}
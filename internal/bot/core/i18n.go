@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/metrics"
+	"telegram-anonymous-bot/internal/translate"
+)
+
+// Catalog возвращает каталог текстов бота (встроенный, если в BotCore не задан свой).
+func (bc *BotCore) Catalog() *i18n.Catalog {
+	if bc.I18n != nil {
+		return bc.I18n
+	}
+	return i18n.Default()
+}
+
+// Lang определяет язык пользователя, написавшего сообщение.
+func (bc *BotCore) Lang(ctx context.Context, u *tgbotapi.User) string {
+	return bc.UserLang(ctx, u.ID, u.LanguageCode)
+}
+
+// UserLang определяет язык пользователя: выбранный через /language, иначе hint
+// (язык клиента Telegram или определённый по тексту), иначе язык по умолчанию.
+func (bc *BotCore) UserLang(ctx context.Context, userID int64, hint string) string {
+	catalog := bc.Catalog()
+
+	lang, err := bc.Storage.GetUserLanguage(int(userID))
+	if err != nil {
+		LogStorageError(ctx, "GetUserLanguage", err)
+	}
+	if lang != "" && catalog.Supports(lang) {
+		return lang
+	}
+	if hint = translate.NormalizeLanguage(hint); catalog.Supports(hint) {
+		return hint
+	}
+	return bc.DefaultLang()
+}
+
+// DefaultLang возвращает язык бота по умолчанию (DEFAULT_LANGUAGE).
+func (bc *BotCore) DefaultLang() string {
+	if lang := bc.Config.DefaultLanguage; bc.Catalog().Supports(lang) {
+		return lang
+	}
+	return bc.Catalog().Fallback()
+}
+
+// T возвращает текст по ключу на языке lang.
+func (bc *BotCore) T(lang, key string, params ...i18n.Params) string {
+	return bc.Catalog().T(lang, key, params...)
+}
+
+// N возвращает текст по ключу с формой множественного числа для n.
+func (bc *BotCore) N(lang, key string, n int, params ...i18n.Params) string {
+	return bc.Catalog().N(lang, key, n, params...)
+}
+
+// Reply отвечает в чат сообщения текстом по ключу на языке отправителя.
+// Если среди параметров есть "error", ошибка пишется в журнал вместе с ключом ответа.
+func (bc *BotCore) Reply(ctx context.Context, msg *tgbotapi.Message, key string, params ...i18n.Params) {
+	logReplyError(ctx, key, params)
+	bc.SendMessage(ctx, msg.Chat.ID, bc.T(bc.Lang(ctx, msg.From), key, params...))
+}
+
+// AnswerCallback отвечает на нажатие inline-кнопки текстом по ключу на языке нажавшего.
+// Ошибка из параметра "error" пишется в журнал, как в Reply.
+func (bc *BotCore) AnswerCallback(ctx context.Context, cb *tgbotapi.CallbackQuery, key string, params ...i18n.Params) {
+	logReplyError(ctx, key, params)
+	bc.Request(ctx, tgbotapi.NewCallback(cb.ID, bc.T(bc.Lang(ctx, cb.From), key, params...)))
+}
+
+// logReplyError пишет в журнал ошибку, о которой сообщает ответ: хендлеры показывают
+// ошибки базы и нейросети через параметр "error", так они не теряются и в журнале,
+// а команда в метриках считается завершённой с ошибкой.
+func logReplyError(ctx context.Context, key string, params []i18n.Params) {
+	for _, p := range params {
+		if err, ok := p["error"].(error); ok {
+			slog.ErrorContext(ctx, "Ошибка обработки", "reply", key, "error", err)
+			metrics.SetOutcome(ctx, metrics.OutcomeError)
+		}
+	}
+}
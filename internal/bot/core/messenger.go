@@ -0,0 +1,10 @@
+package core
+
+import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+// Messenger — часть Bot API, через которую бот отправляет сообщения, медиа и правки
+// и выполняет служебные запросы. Реализуется *tgbotapi.BotAPI, в тестах — telegramtest.Recorder.
+type Messenger interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/storage"
+)
+
+// Действия администратора с вопросом. Через них работают и команды бота, и веб-панель,
+// поэтому проверки и уведомления автора одинаковы в обоих интерфейсах.
+
+var (
+	// ErrAlreadyAnswered — на вопрос уже ответили.
+	ErrAlreadyAnswered = errors.New("на вопрос уже ответили")
+	// ErrRejected — вопрос отклонён.
+	ErrRejected = errors.New("вопрос отклонён")
+)
+
+// CheckOpen возвращает ErrAlreadyAnswered или ErrRejected, если вопрос уже закрыт.
+func CheckOpen(q *models.Question) error {
+	switch {
+	case q.Answered:
+		return ErrAlreadyAnswered
+	case q.Rejected:
+		return ErrRejected
+	}
+	return nil
+}
+
+// AnswerQuestion отправляет ответ на открытый вопрос (см. DeliverAnswer).
+func (bc *BotCore) AnswerQuestion(ctx context.Context, q *models.Question, answerText string) error {
+	if err := CheckOpen(q); err != nil {
+		return err
+	}
+	return bc.DeliverAnswer(ctx, q, answerText)
+}
+
+// RejectQuestion отклоняет открытый вопрос: автор получает уведомление с причиной
+// (если она указана) на своём языке, вопрос пропадает из неотвеченных.
+func (bc *BotCore) RejectQuestion(ctx context.Context, q *models.Question, reason string) error {
+	if err := CheckOpen(q); err != nil {
+		return err
+	}
+
+	previous := *q
+	q.Rejected = true
+	q.Answer = strings.TrimSpace(reason)
+	if err := bc.closeQuestion(q, previous); err != nil {
+		return err
+	}
+
+	lang := bc.UserLang(ctx, int64(q.UserID), q.Language)
+	if q.Answer == "" {
+		bc.SendMessage(ctx, int64(q.UserID), bc.T(lang, "reject.delivered", i18n.Params{"id": q.ID}))
+		return nil
+	}
+	bc.SendMessage(ctx, int64(q.UserID), bc.T(lang, "reject.delivered_reason", i18n.Params{
+		"id":     q.ID,
+		"reason": bc.translateAnswer(ctx, q, q.Answer),
+	}))
+	return nil
+}
+
+// closeQuestion сохраняет ответ или отказ, только если вопрос всё ещё открыт в базе.
+// Поля Answered, Rejected и Answer вызывающий уже заполнил; previous — их значения до этого,
+// к ним вопрос возвращается, если сохранить не удалось. Уже закрытый другим администратором
+// вопрос даёт ErrAlreadyAnswered.
+func (bc *BotCore) closeQuestion(q *models.Question, previous models.Question) error {
+	err := bc.Storage.CloseQuestion(q)
+	if err == nil {
+		return nil
+	}
+	q.Answered, q.Rejected, q.Answer = previous.Answered, previous.Rejected, previous.Answer
+	if errors.Is(err, storage.ErrQuestionClosed) {
+		return ErrAlreadyAnswered
+	}
+	return err
+}
+
+// TagQuestion заменяет темы вопроса; категория классификатора не меняется.
+func (bc *BotCore) TagQuestion(ctx context.Context, q *models.Question, tags []string) error {
+	if err := bc.Storage.UpdateClassification(q.ID, q.Category, tags); err != nil {
+		return err
+	}
+	q.Tags = tags
+	return nil
+}
+
+// ParseTags разбирает темы, введённые администратором: через пробел или запятую,
+// с решёткой или без. Темы приводятся к нижнему регистру, повторы убираются.
+func ParseTags(input string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		tag = strings.ToLower(strings.TrimLeft(tag, "#"))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
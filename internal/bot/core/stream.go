@@ -0,0 +1,158 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/llm"
+)
+
+const (
+	// MaxMessageLength — ограничение Telegram на длину текста сообщения (в символах).
+	MaxMessageLength = 4096
+	// typingRefresh — статус «печатает» гаснет через ~5 секунд, обновляем его чуть чаще.
+	typingRefresh = 4 * time.Second
+	// streamPlaceholder — текст сообщения до прихода первых токенов.
+	streamPlaceholder = "…"
+)
+
+// StreamChat отправляет диалог нейросети и показывает ответ по мере генерации:
+// сначала сообщение-заглушка и статус «печатает», затем правки этого сообщения
+// не чаще Config.StreamEditInterval, в конце — полный текст (длинный делится на части).
+// lang — язык сообщения об ошибке.
+func (bc *BotCore) StreamChat(ctx context.Context, chatID int64, lang string, messages []llm.Message) (string, error) {
+	if bc.LLM == nil {
+		return "", errLLMNotConfigured
+	}
+
+	r := &streamRenderer{bc: bc, ctx: ctx, chatID: chatID, interval: bc.Config.StreamEditInterval}
+	r.start()
+
+	llmCtx, cancel := bc.llmContext(ctx)
+	defer cancel()
+
+	text, err := llm.StreamChat(llmCtx, bc.LLM, messages, r.onDelta)
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка нейросети", "error", err)
+		r.fail(bc.T(lang, "common.llm_error", i18n.Params{"error": err}))
+		return "", err
+	}
+	r.finish(text)
+	return text, nil
+}
+
+// streamRenderer выводит потоковый ответ правками одного сообщения.
+type streamRenderer struct {
+	bc       *BotCore
+	ctx      context.Context
+	chatID   int64
+	interval time.Duration
+
+	messageID  int // 0 — заглушку отправить не удалось
+	text       strings.Builder
+	shown      string
+	lastEdit   time.Time
+	lastTyping time.Time
+}
+
+func (r *streamRenderer) start() {
+	r.typing()
+	placeholder := tgbotapi.NewMessage(r.chatID, streamPlaceholder)
+	msg, err := r.bc.Messenger.Send(placeholder)
+	if err != nil {
+		LogTelegramError(r.ctx, placeholder, err)
+		return
+	}
+	r.messageID = msg.MessageID
+	r.lastEdit = time.Now()
+}
+
+func (r *streamRenderer) onDelta(delta string) {
+	r.text.WriteString(delta)
+
+	now := time.Now()
+	if now.Sub(r.lastTyping) >= typingRefresh {
+		r.typing()
+	}
+	if now.Sub(r.lastEdit) < r.interval {
+		return
+	}
+
+	// Пока ответ генерируется, показываем только то, что помещается в одно сообщение
+	parts := SplitMessage(r.text.String(), MaxMessageLength)
+	if len(parts) > 0 {
+		r.edit(parts[0])
+	}
+	r.lastEdit = now
+}
+
+func (r *streamRenderer) finish(text string) {
+	parts := SplitMessage(text, MaxMessageLength)
+	if len(parts) == 0 {
+		parts = []string{streamPlaceholder}
+	}
+
+	if r.messageID == 0 {
+		for _, p := range parts {
+			r.bc.SendMessage(r.ctx, r.chatID, p)
+		}
+		return
+	}
+
+	r.edit(parts[0])
+	for _, p := range parts[1:] {
+		r.bc.SendMessage(r.ctx, r.chatID, p)
+	}
+}
+
+func (r *streamRenderer) fail(text string) {
+	if r.messageID == 0 {
+		r.bc.SendMessage(r.ctx, r.chatID, text)
+		return
+	}
+	r.edit(text)
+}
+
+// edit меняет текст заглушки; одинаковый текст Telegram отклоняет, поэтому такие правки пропускаем.
+func (r *streamRenderer) edit(text string) {
+	if r.messageID == 0 || text == r.shown {
+		return
+	}
+	r.bc.Request(r.ctx, tgbotapi.NewEditMessageText(r.chatID, r.messageID, text))
+	r.shown = text
+}
+
+func (r *streamRenderer) typing() {
+	r.bc.Request(r.ctx, tgbotapi.NewChatAction(r.chatID, tgbotapi.ChatTyping))
+	r.lastTyping = time.Now()
+}
+
+// SplitMessage делит текст на части не длиннее limit символов,
+// по возможности по границе абзаца, строки или слова.
+func SplitMessage(text string, limit int) []string {
+	var parts []string
+	for utf8.RuneCountInString(text) > limit {
+		runes := []rune(text)
+		chunk := string(runes[:limit])
+
+		cut := len(chunk)
+		for _, sep := range []string{"\n\n", "\n", " "} {
+			if i := strings.LastIndex(chunk, sep); i > len(chunk)/2 {
+				cut = i + len(sep)
+				break
+			}
+		}
+
+		parts = append(parts, strings.TrimRight(chunk[:cut], " \n"))
+		text = strings.TrimLeft(text[cut:], " \n")
+	}
+	if strings.TrimSpace(text) != "" {
+		parts = append(parts, text)
+	}
+	return parts
+}
@@ -0,0 +1,36 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"telegram-anonymous-bot/internal/bot/core"
+)
+
+func TestSplitMessage(t *testing.T) {
+	if got := core.SplitMessage("короткий текст", 4096); len(got) != 1 || got[0] != "короткий текст" {
+		t.Errorf("Expected short text unchanged, got %v", got)
+	}
+
+	text := strings.Repeat("абв ", 30) + "\n\n" + strings.Repeat("где ", 30)
+	parts := core.SplitMessage(text, 150)
+	if len(parts) < 2 {
+		t.Fatalf("Expected text to be split, got %v", parts)
+	}
+	for _, p := range parts {
+		if n := utf8.RuneCountInString(p); n > 150 {
+			t.Errorf("Part is %d characters long, limit is 150", n)
+		}
+	}
+	// Разрез по границе абзаца
+	if !strings.HasSuffix(parts[0], "абв") || !strings.HasPrefix(parts[1], "где") {
+		t.Errorf("Expected split at paragraph boundary, got %q | %q", parts[0], parts[1])
+	}
+
+	// Текст без пробелов режется ровно по лимиту
+	parts = core.SplitMessage(strings.Repeat("я", 250), 100)
+	if len(parts) != 3 || utf8.RuneCountInString(parts[2]) != 50 {
+		t.Errorf("Expected 100+100+50 runes, got %v", parts)
+	}
+}
@@ -0,0 +1,339 @@
+package bot_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-anonymous-bot/internal/bot"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/bot/telegramtest"
+	"telegram-anonymous-bot/internal/config"
+	"telegram-anonymous-bot/internal/health"
+	"telegram-anonymous-bot/internal/storage"
+)
+
+// waitMessages ждёт, пока в чат придёт n сообщений, и возвращает их. srv — telegramtest.Server
+// или telegramtest.Recorder.
+func waitMessages(t *testing.T, srv interface{ Messages(int64) []string }, chatID int64, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := srv.Messages(chatID)
+		if len(got) >= n {
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d messages in chat %d, got %v", n, chatID, got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// freeAddr возвращает свободный локальный адрес для HTTP-сервера бота.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// TestEndToEnd прогоняет настоящий клиент tgbotapi, SQLite и long polling через
+// telegramtest.Server: вопрос → /list → /answer → доставка ответа автору.
+func TestEndToEnd(t *testing.T) {
+	srv := telegramtest.NewServer()
+	defer srv.Close()
+
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "bot.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage failed: %v", err)
+	}
+	defer store.Close()
+
+	const user, admin = 12345, 999999
+	logPath := filepath.Join(t.TempDir(), "updates.jsonl")
+	statusAddr := freeAddr(t)
+	telegramBot, err := bot.NewTelegramBot(&config.Config{
+		TelegramBotToken:    "fake_token",
+		TelegramAPIEndpoint: srv.Endpoint(),
+		AdminID:             admin,
+		LLMProvider:         "fake",
+		Workers:             2,
+		WorkerQueueSize:     4,
+		UpdatesLog:          logPath,
+		UpdatesLogRedact:    true,
+		StatusListen:        statusAddr,
+		TelegramCheckEvery:  10 * time.Millisecond,
+		ReadyTelegramMaxAge: 200 * time.Millisecond,
+	}, store)
+	if err != nil {
+		t.Fatalf("NewTelegramBot failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- telegramBot.Start(ctx) }()
+
+	srv.PushText(user, "Когда начнётся сессия?")
+	if got := waitMessages(t, srv, user, 1); !strings.Contains(got[0], "ID=1") {
+		t.Errorf("Expected confirmation with question ID, got %v", got)
+	}
+	if got := waitMessages(t, srv, admin, 1); !strings.Contains(got[0], "Когда начнётся сессия?") {
+		t.Errorf("Expected admin notification, got %v", got)
+	}
+
+	srv.PushText(admin, "/list")
+	if got := waitMessages(t, srv, admin, 2); !strings.Contains(got[1], "ID: 1") || !strings.Contains(got[1], "Ответил: нет") {
+		t.Errorf("Expected unanswered question in /list, got %v", got[1])
+	}
+
+	srv.PushText(admin, "/answer 1 С 10 января.")
+	if got := waitMessages(t, srv, user, 2); got[1] != "Ответ на ваш вопрос (ID=1):\nС 10 января." {
+		t.Errorf("Expected the answer to be delivered, got %v", got[1])
+	}
+	waitMessages(t, srv, admin, 3)
+
+	srv.PushText(admin, "/list")
+	if got := waitMessages(t, srv, admin, 4); !strings.Contains(got[3], "Ответил: да") {
+		t.Errorf("Expected answered question in /list, got %v", got[3])
+	}
+
+	// Метрика команды пишется после ответа, поэтому ждём, пока учтётся последний /list
+	var body []byte
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		resp, err := http.Get("http://" + statusAddr + bot.MetricsPath)
+		if err != nil {
+			t.Fatalf("Metrics request failed: %v", err)
+		}
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if strings.Contains(string(body), `tgbot_commands_total{command="list",outcome="ok"} 2`) {
+			break
+		}
+	}
+	for _, want := range []string{
+		`tgbot_updates_total{type="message"} 4`,
+		`tgbot_commands_total{command="list",outcome="ok"} 2`,
+		`tgbot_commands_total{command="message",outcome="ok"} 1`,
+		`tgbot_questions_created_total 1`,
+		`tgbot_questions_answered_total 1`,
+		`tgbot_answer_latency_seconds_count 1`,
+		`tgbot_telegram_request_duration_seconds_count{method="sendMessage"}`,
+		`tgbot_storage_query_duration_seconds_count{op="SaveQuestion"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected %s in metrics, got:\n%s", want, body)
+		}
+	}
+
+	// getMe при создании клиента уже устарел: готовность держится на периодической проверке
+	time.Sleep(300 * time.Millisecond)
+	for _, path := range []string{bot.HealthPath, bot.ReadyPath} {
+		resp, err := http.Get("http://" + statusAddr + path)
+		if err != nil {
+			t.Fatalf("%s request failed: %v", path, err)
+		}
+		var report health.Report
+		err = json.NewDecoder(resp.Body).Decode(&report)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK || report.Status != health.StatusOK {
+			t.Errorf("Expected %s to be ok, got %d %+v (%v)", path, resp.StatusCode, report, err)
+		}
+		if path == bot.ReadyPath && len(report.Components) != 3 {
+			t.Errorf("Expected storage, telegram and updates checks, got %+v", report.Components)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start returned error: %v", err)
+	}
+	telegramBot.Shutdown(time.Second)
+
+	if len(srv.Calls("setMyCommands")) == 0 {
+		t.Errorf("Expected command menus to be published on start")
+	}
+	if last, err := store.GetLastUpdateID(); err != nil || last != 4 {
+		t.Errorf("Expected last update ID 4 to be saved, got %d (%v)", last, err)
+	}
+
+	// Журнал воспроизводит тот же сценарий на чистой базе; текст вопроса в нём скрыт
+	replayStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "replay.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage failed: %v", err)
+	}
+	defer replayStore.Close()
+	bc, err := bot.NewBotCore(&config.Config{AdminID: admin, LLMProvider: "fake", Workers: 1, WorkerQueueSize: 4}, replayStore)
+	if err != nil {
+		t.Fatalf("NewBotCore failed: %v", err)
+	}
+	tg := &telegramtest.Recorder{}
+	bc.Messenger = tg
+	replayBot := bot.NewTelegramBotWithCore(bc, nil)
+
+	file, err := os.Open(logPath)
+	if err != nil {
+		t.Fatalf("Updates log was not written: %v", err)
+	}
+	defer file.Close()
+	if n, err := replayBot.Replay(file); err != nil || n != 4 {
+		t.Fatalf("Expected 4 replayed updates, got %d (%v)", n, err)
+	}
+	replayBot.Shutdown(time.Second)
+
+	if got := tg.Messages(user); len(got) != 2 || got[1] != "Ответ на ваш вопрос (ID=1):\nС 10 января." {
+		t.Errorf("Expected the replay to deliver the same answer, got %v", got)
+	}
+	if got := tg.Messages(admin); len(got) != 4 || strings.Contains(got[0], "Когда начнётся сессия?") {
+		t.Errorf("Expected the replayed question text to be redacted, got %v", got)
+	}
+	if last, _ := replayStore.GetLastUpdateID(); last != 0 {
+		t.Errorf("Replay must not save update IDs, got %d", last)
+	}
+}
+
+// TestWebLoginLink проверяет вход в веб-панель по ссылке из команды /web.
+func TestWebLoginLink(t *testing.T) {
+	srv := telegramtest.NewServer()
+	defer srv.Close()
+
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "bot.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage failed: %v", err)
+	}
+	defer store.Close()
+
+	const user, admin = 12345, 999999
+	webAddr := freeAddr(t)
+	telegramBot, err := bot.NewTelegramBot(&config.Config{
+		TelegramBotToken:    "fake_token",
+		TelegramAPIEndpoint: srv.Endpoint(),
+		AdminID:             admin,
+		LLMProvider:         "fake",
+		Workers:             1,
+		WorkerQueueSize:     4,
+		WebListen:           webAddr,
+		WebURL:              "http://" + webAddr,
+	}, store)
+	if err != nil {
+		t.Fatalf("NewTelegramBot failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- telegramBot.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+		telegramBot.Shutdown(time.Second)
+	}()
+
+	srv.PushText(user, "/web")
+	srv.PushText(admin, "/web")
+	got := waitMessages(t, srv, admin, 1)
+	link := got[0][strings.Index(got[0], "http://"):]
+	if i := strings.IndexAny(link, " \n"); i >= 0 {
+		link = link[:i]
+	}
+	if !strings.HasPrefix(link, "http://"+webAddr+"/login/link?token=") {
+		t.Fatalf("Expected a login link, got %q", got[0])
+	}
+	if msgs := waitMessages(t, srv, user, 1); strings.Contains(msgs[0], "http://") {
+		t.Errorf("Expected /web to be refused for users, got %v", msgs)
+	}
+
+	resp, err := http.Get(link)
+	if err != nil {
+		t.Fatalf("Login link request failed: %v", err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(page), `action="/login/link"`) {
+		t.Errorf("Expected a login confirmation page, got %d:\n%s", resp.StatusCode, page)
+	}
+}
+
+// TestPollingStopKeepsUnqueuedUpdates останавливает бота, пока часть полученных обновлений
+// ещё не поставлена в очередь: Telegram не должен считать их полученными, и после
+// перезапуска они обрабатываются — ровно по разу.
+func TestPollingStopKeepsUnqueuedUpdates(t *testing.T) {
+	srv := telegramtest.NewServer()
+	defer srv.Close()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "bot.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage failed: %v", err)
+	}
+	defer store.Close()
+
+	const user = 12345
+	for range 5 {
+		srv.PushText(user, "/start")
+	}
+	start := func(tg *telegramtest.Recorder) (*bot.TelegramBot, context.CancelFunc, <-chan error) {
+		api, err := tgbotapi.NewBotAPIWithAPIEndpoint("fake_token", srv.Endpoint())
+		if err != nil {
+			t.Fatalf("NewBotAPI failed: %v", err)
+		}
+		telegramBot := bot.NewTelegramBotWithCore(&core.BotCore{
+			Messenger: tg,
+			Config:    &config.Config{AdminID: 999999, Workers: 1, WorkerQueueSize: 1},
+			Storage:   store,
+		}, api)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- telegramBot.Start(ctx) }()
+		return telegramBot, cancel, done
+	}
+
+	// Первый запуск: обработчик завис на первом обновлении, второе в очереди, третье ждёт места
+	// (Queued учитывает и его)
+	release := make(chan struct{})
+	first := &telegramtest.Recorder{Hook: func(c telegramtest.Call) error {
+		if c.Method == "sendMessage" {
+			<-release
+		}
+		return nil
+	}}
+	telegramBot, cancel, done := start(first)
+	deadline := time.Now().Add(5 * time.Second)
+	for stats := telegramBot.WorkerStats(); stats.InFlight != 1 || stats.Queued != 2; stats = telegramBot.WorkerStats() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Queue was not filled, worker stats: %+v", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start returned error: %v", err)
+	}
+	telegramBot.Shutdown(50 * time.Millisecond)
+	close(release)
+	if got := waitMessages(t, first, user, 2); len(got) != 2 {
+		t.Errorf("Expected the started and queued updates to finish, got %v", got)
+	}
+	telegramBot.Shutdown(time.Second)
+
+	// Второй запуск получает оставшиеся три обновления
+	second := &telegramtest.Recorder{}
+	telegramBot, cancel, done = start(second)
+	got := waitMessages(t, second, user, 3)
+	cancel()
+	<-done
+	telegramBot.Shutdown(time.Second)
+	if len(got) != 3 || len(first.Messages(user)) != 2 {
+		t.Errorf("Expected every update handled exactly once, got %d + %d greetings", len(first.Messages(user)), len(got))
+	}
+}
@@ -1,13 +1,30 @@
 package bot_test
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/stretchr/testify/mock"
 
 	"telegram-anonymous-bot/internal/bot" // <-- Пакет, где лежит TelegramBot
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/bot/handlers"
+	"telegram-anonymous-bot/internal/bot/telegramtest"
+	"telegram-anonymous-bot/internal/config"
+	"telegram-anonymous-bot/internal/crisis"
+	"telegram-anonymous-bot/internal/filter"
+	"telegram-anonymous-bot/internal/llm"
 	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/storage"
+	"telegram-anonymous-bot/internal/translate"
 )
 
 // -------------------- Моки -------------------- //
@@ -26,9 +43,153 @@ func (m *MockStorage) GetQuestion(id int) (*models.Question, error) {
 	// Если нужно, возвращаем заранее заданный результат
 	return args.Get(0).(*models.Question), args.Error(1)
 }
+func (m *MockStorage) GetAllQuestions() ([]*models.Question, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Question), args.Error(1)
+}
+func (m *MockStorage) GetLastQuestionID() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+func (m *MockStorage) UpdateQuestion(q *models.Question) error {
+	args := m.Called(q)
+	return args.Error(0)
+}
+func (m *MockStorage) CloseQuestion(q *models.Question) error {
+	args := m.Called(q)
+	return args.Error(0)
+}
+func (m *MockStorage) UpdateClassification(id int, category string, tags []string) error {
+	args := m.Called(id, category, tags)
+	return args.Error(0)
+}
+func (m *MockStorage) SetHeld(id int, held bool) error {
+	args := m.Called(id, held)
+	return args.Error(0)
+}
+func (m *MockStorage) UpdateTranslation(id int, language, translation string) error {
+	args := m.Called(id, language, translation)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SaveTemplate(t *models.Template) error {
+	args := m.Called(t)
+	return args.Error(0)
+}
+func (m *MockStorage) GetTemplate(id int) (*models.Template, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Template), args.Error(1)
+}
+func (m *MockStorage) GetTemplateByName(name string) (*models.Template, error) {
+	args := m.Called(name)
+	return args.Get(0).(*models.Template), args.Error(1)
+}
+func (m *MockStorage) GetAllTemplates() ([]*models.Template, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.Template), args.Error(1)
+}
+func (m *MockStorage) DeleteTemplate(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SaveDraft(d *models.Draft) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
+func (m *MockStorage) GetDraft(id int) (*models.Draft, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Draft), args.Error(1)
+}
+func (m *MockStorage) DeleteDraft(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockStorage) AddChatMessage(msg *models.ChatMessage) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+func (m *MockStorage) GetChatMessages(userID, limit int) ([]*models.ChatMessage, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]*models.ChatMessage), args.Error(1)
+}
+func (m *MockStorage) ClearChat(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
 
-// Если в вашем Storage есть и другие методы (UpdateQuestion, GetAllQuestions и т.д.),
-// их тоже нужно замокать, иначе во время теста может быть паника.
+// GetUserLanguage не требует настройки ожидания: без него язык пользователем не выбран.
+func (m *MockStorage) GetUserLanguage(userID int) (string, error) {
+	for _, c := range m.ExpectedCalls {
+		if c.Method == "GetUserLanguage" {
+			args := m.Called(userID)
+			return args.String(0), args.Error(1)
+		}
+	}
+	return "", nil
+}
+func (m *MockStorage) SetUserLanguage(userID int, language string) error {
+	args := m.Called(userID, language)
+	return args.Error(0)
+}
+func (m *MockStorage) GetLastUpdateID() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+func (m *MockStorage) SetLastUpdateID(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+func (m *MockStorage) GetState(key string) (string, error) {
+	args := m.Called(key)
+	return args.String(0), args.Error(1)
+}
+func (m *MockStorage) SetState(key, value string) error {
+	args := m.Called(key, value)
+	return args.Error(0)
+}
+func (m *MockStorage) Ping() error {
+	args := m.Called()
+	return args.Error(0)
+}
+func (m *MockStorage) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+func (m *MockStorage) BanUser(userID int, reason string) error {
+	args := m.Called(userID, reason)
+	return args.Error(0)
+}
+func (m *MockStorage) UnbanUser(userID int) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockStorage) IsBanned(userID int) (bool, error) {
+	// Как и GetUserLanguage: без ожидания — никто не забанен
+	for _, c := range m.ExpectedCalls {
+		if c.Method == "IsBanned" {
+			args := m.Called(userID)
+			return args.Bool(0), args.Error(1)
+		}
+	}
+	return false, nil
+}
+
+// newTestBot собирает бота, отправляющего сообщения в telegramtest.Recorder.
+func newTestBot(store *MockStorage) (*bot.TelegramBot, *telegramtest.Recorder) {
+	return newTestBotWithLLM(store, &llm.Fake{})
+}
+
+func newTestBotWithLLM(store *MockStorage, provider llm.Provider) (*bot.TelegramBot, *telegramtest.Recorder) {
+	tg := &telegramtest.Recorder{}
+	return bot.NewTelegramBotWithCore(&core.BotCore{
+		Messenger: tg,
+		Config:    &config.Config{TelegramBotToken: "fake_token", AdminID: 999999, SuggestExamples: 3, ChatHistoryLimit: 10, ChatHistoryTokens: 1000, ChatSessionTTL: time.Hour},
+		Storage:   store,
+		LLM:       provider,
+	}, nil), tg
+}
 
 // -------------------- Тест -------------------- //
 
@@ -38,15 +199,10 @@ func TestHandleMessage(t *testing.T) {
 
 	// Ожидаем, что при сохранении вопроса всё ок (возврат nil в качестве ошибки)
 	storageMock.On("SaveQuestion", mock.Anything).Return(nil)
+	storageMock.On("GetAllTemplates").Return([]*models.Template{}, nil)
 
-	// 2. Создаём "бота" — для теста достаточно минимальных полей
-	telegramBot := &bot.TelegramBot{
-		// bot.BotAPI можно замокать отдельно,
-		// но если метод HandleMessage не требует реальных вызовов к Telegram, оставим nil
-		Bot:     nil,
-		Config:  &bot.Config{TelegramBotToken: "fake_token", AdminID: 999999},
-		Storage: storageMock,
-	}
+	// 2. Создаём "бота" — Telegram API подменён telegramtest.Recorder
+	telegramBot, tg := newTestBot(storageMock)
 
 	// 3. Подготовим тестовое сообщение (только текст)
 	message := &tgbotapi.Message{
@@ -55,25 +211,832 @@ func TestHandleMessage(t *testing.T) {
 			ID:       12345,
 			UserName: "testuser",
 		},
-		// Chat.ID — куда бот вернёт ответ (в вашем коде, если отправляете сообщения)
+		// Chat.ID — куда бот вернёт ответ
 		Chat: &tgbotapi.Chat{ID: 11111},
 	}
 
 	// 4. Вызываем тестируемый метод
 	telegramBot.HandleMessage(message)
 
-	// 5. Проверяем, что метод SaveQuestion был вызван
-	storageMock.AssertCalled(t, "SaveQuestion", mock.Anything)
+	// 5. Проверяем, что SaveQuestion был вызван с данными сообщения
+	storageMock.AssertCalled(t, "SaveQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		return q.UserID == 12345 && q.Username == "testuser" && q.Text == "Hello world!"
+	}))
+
+	if got := tg.Messages(11111); len(got) != 1 {
+		t.Errorf("Expected 1 confirmation to the sender, got %v", got)
+	}
+	if got := tg.Messages(999999); len(got) != 1 || !strings.Contains(got[0], "Hello world!") {
+		t.Errorf("Expected admin notification with question text, got %v", got)
+	}
+}
+
+func TestAnswerWithTemplate(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetQuestion", 7).Return(&models.Question{ID: 7, UserID: 12345, Text: "Когда?"}, nil)
+	storageMock.On("GetTemplateByName", "thanks").
+		Return(&models.Template{ID: 1, Name: "thanks", Text: "Спасибо за вопрос #{{.ID}}!"}, nil)
+	storageMock.On("CloseQuestion", mock.Anything).Return(nil)
+
+	telegramBot, tg := newTestBot(storageMock)
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/answer 7 #thanks",
+		From:     &tgbotapi.User{ID: 999999},
+		Chat:     &tgbotapi.Chat{ID: 999999},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 7}},
+	})
+
+	storageMock.AssertCalled(t, "CloseQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		return q.Answered && q.Answer == "Спасибо за вопрос #7!"
+	}))
+	if got := tg.Messages(12345); len(got) != 1 || !strings.Contains(got[0], "Спасибо за вопрос #7!") {
+		t.Errorf("Expected rendered template delivered to the asker, got %v", got)
+	}
+	// Неизвестный и сломанный шаблоны — понятный ответ на языке администратора
+	storageMock.On("GetTemplateByName", "missing").Return((*models.Template)(nil), fmt.Errorf("%w: %q", storage.ErrTemplateNotFound, "missing"))
+	storageMock.On("GetTemplateByName", "broken").Return(&models.Template{ID: 2, Name: "broken", Text: "{{.Nope}}"}, nil)
+	storageMock.On("GetQuestion", 8).Return(&models.Question{ID: 8, UserID: 12345, Text: "Где?"}, nil)
+	for _, name := range []string{"missing", "broken"} {
+		telegramBot.HandleMessage(&tgbotapi.Message{
+			Text:     "/answer 8 #" + name,
+			From:     &tgbotapi.User{ID: 999999, LanguageCode: "en"},
+			Chat:     &tgbotapi.Chat{ID: 999999},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 7}},
+		})
+	}
+	got := tg.Messages(999999)
+	if len(got) != 3 || got[1] != "Template #missing not found." || !strings.HasPrefix(got[2], "Error in template #broken: ") {
+		t.Errorf("Expected localized template errors, got %v", got)
+	}
+}
+
+func TestAnswerRace(t *testing.T) {
+	// Вопрос открыт при чтении, но другой администратор закрыл его раньше
+	storageMock := new(MockStorage)
+	question := &models.Question{ID: 7, UserID: 12345, Text: "Когда?"}
+	storageMock.On("GetQuestion", 7).Return(question, nil)
+	storageMock.On("CloseQuestion", mock.Anything).Return(storage.ErrQuestionClosed)
+	telegramBot, tg := newTestBot(storageMock)
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/answer 7 Завтра",
+		From:     &tgbotapi.User{ID: 999999},
+		Chat:     &tgbotapi.Chat{ID: 999999},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 7}},
+	})
+
+	if got := tg.Messages(12345); len(got) != 0 {
+		t.Errorf("Expected no second answer to the asker, got %v", got)
+	}
+	if got := tg.Messages(999999); len(got) != 1 || !strings.Contains(got[0], "уже был дан ответ") {
+		t.Errorf("Expected the admin to be told the question is already answered, got %v", got)
+	}
+	if question.Answered || question.Answer != "" {
+		t.Errorf("Expected the question fields to be restored, got answered=%v answer=%q", question.Answered, question.Answer)
+	}
+}
+
+func TestSuggestDraftCallbacks(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetDraft", 1).Return(&models.Draft{ID: 1, QuestionID: 7, Text: "Черновик"}, nil)
+	storageMock.On("GetQuestion", 7).Return(&models.Question{ID: 7, UserID: 12345, Text: "Когда?"}, nil)
+	storageMock.On("CloseQuestion", mock.Anything).Return(nil)
+	storageMock.On("DeleteDraft", 1).Return(nil)
+
+	telegramBot, tg := newTestBot(storageMock)
+	admin := &tgbotapi.User{ID: 999999}
+
+	// «Удалить» не должно ничего отправлять пользователю
+	telegramBot.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{ID: "1", From: admin, Data: "sug:drop:1"}})
+	if got := tg.Messages(12345); len(got) != 0 {
+		t.Fatalf("Expected nothing sent to the asker on discard, got %v", got)
+	}
+	storageMock.AssertNotCalled(t, "CloseQuestion", mock.Anything)
+
+	// «Отправить» доставляет черновик как есть
+	telegramBot.HandleUpdate(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{ID: "2", From: admin, Data: "sug:send:1"}})
+	if got := tg.Messages(12345); len(got) != 1 || !strings.Contains(got[0], "Черновик") {
+		t.Errorf("Expected draft delivered to the asker, got %v", got)
+	}
+}
+
+func TestSuggestUsesProvider(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetQuestion", 7).Return(&models.Question{ID: 7, UserID: 12345, Text: "Когда?"}, nil)
+	storageMock.On("GetAllQuestions").Return([]*models.Question{
+		{ID: 3, Text: "Где?", Answered: true, Answer: "Здесь."},
+	}, nil)
+	storageMock.On("SaveDraft", mock.Anything).Return(nil)
+
+	provider := &llm.Fake{Responses: []string{"Скоро."}}
+	telegramBot, tg := newTestBotWithLLM(storageMock, provider)
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/suggest 7",
+		From:     &tgbotapi.User{ID: 999999, LanguageCode: "en"},
+		Chat:     &tgbotapi.Chat{ID: 999999},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 8}},
+	})
+
+	prompts := provider.Prompts()
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "Когда?") || !strings.Contains(prompts[0], "Здесь.") {
+		t.Fatalf("Expected prompt with question and style example, got %v", prompts)
+	}
+	if !strings.Contains(prompts[0], "(код en)") {
+		t.Errorf("Expected the draft to be requested in the admin's language, got %q", prompts[0])
+	}
+	storageMock.AssertCalled(t, "SaveDraft", mock.MatchedBy(func(d *models.Draft) bool {
+		return d.QuestionID == 7 && d.Text == "Скоро."
+	}))
+	if got := tg.Messages(12345); len(got) != 0 {
+		t.Errorf("Draft must not reach the asker without admin action, got %v", got)
+	}
+}
+
+func TestAskSendsHistory(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetChatMessages", 12345, 10).Return([]*models.ChatMessage{
+		{UserID: 12345, Role: "user", Content: "Меня зовут Аня", CreatedAt: time.Now()},
+		{UserID: 12345, Role: "assistant", Content: "Приятно познакомиться!", CreatedAt: time.Now()},
+	}, nil)
+	storageMock.On("AddChatMessage", mock.Anything).Return(nil)
+
+	provider := &llm.Fake{Responses: []string{"Аня"}}
+	telegramBot, tg := newTestBotWithLLM(storageMock, provider)
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/ask Как меня зовут?",
+		From:     &tgbotapi.User{ID: 12345},
+		Chat:     &tgbotapi.Chat{ID: 12345},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 4}},
+	})
+
+	chats := provider.Chats()
+	if len(chats) != 1 || len(chats[0]) != 4 {
+		t.Fatalf("Expected system prompt, 2 history turns and the question, got %v", chats)
+	}
+	if chats[0][1].Content != "Меня зовут Аня" || chats[0][3].Content != "Как меня зовут?" {
+		t.Errorf("Unexpected messages sent to provider: %v", chats[0])
+	}
+	// Сначала заглушка, затем правки до полного ответа
+	if got := tg.Messages(12345); len(got) != 1 || got[0] != "…" {
+		t.Errorf("Expected a single placeholder message, got %v", got)
+	}
+	if got := tg.Edits(12345); len(got) == 0 || got[len(got)-1] != "Аня" {
+		t.Errorf("Expected placeholder edited to the provider answer, got %v", got)
+	}
+	storageMock.AssertNumberOfCalls(t, "AddChatMessage", 2)
+}
+
+func TestAskExpiredSession(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetChatMessages", 12345, 10).Return([]*models.ChatMessage{
+		{UserID: 12345, Role: "user", Content: "Старый вопрос", CreatedAt: time.Now().Add(-2 * time.Hour)},
+	}, nil)
+	storageMock.On("ClearChat", 12345).Return(nil)
+	storageMock.On("AddChatMessage", mock.Anything).Return(nil)
+
+	provider := &llm.Fake{}
+	telegramBot, _ := newTestBotWithLLM(storageMock, provider)
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/ask Привет",
+		From:     &tgbotapi.User{ID: 12345},
+		Chat:     &tgbotapi.Chat{ID: 12345},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 4}},
+	})
+
+	storageMock.AssertCalled(t, "ClearChat", 12345)
+	if chats := provider.Chats(); len(chats) != 1 || len(chats[0]) != 2 {
+		t.Errorf("Expected only system prompt and the new question, got %v", chats)
+	}
+}
+
+func TestAskStreamsLongAnswer(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetChatMessages", 12345, 10).Return([]*models.ChatMessage{}, nil)
+	storageMock.On("AddChatMessage", mock.Anything).Return(nil)
+
+	long := strings.Repeat("слово ", 1000) // ~6000 символов
+	telegramBot, tg := newTestBotWithLLM(storageMock, &llm.Fake{Responses: []string{long}})
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/ask Расскажи длинно",
+		From:     &tgbotapi.User{ID: 12345},
+		Chat:     &tgbotapi.Chat{ID: 12345},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 4}},
+	})
+
+	edits := tg.Edits(12345)
+	sent := tg.Messages(12345)
+	if len(edits) == 0 || len(sent) != 2 {
+		t.Fatalf("Expected placeholder plus one continuation message, got %d sent, %d edits", len(sent), len(edits))
+	}
+	if len([]rune(edits[len(edits)-1])) > 4096 || len([]rune(sent[1])) > 4096 {
+		t.Errorf("Message parts must not exceed 4096 characters")
+	}
+}
+
+func TestQuestionClassification(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		provider *llm.Fake
+		classify bool
+	}{
+		{name: "urgent", provider: &llm.Fake{Responses: []string{`{"category":"urgent","tags":["здоровье"]}`}}, classify: true},
+		{name: "llm unavailable", provider: &llm.Fake{Err: errors.New("timeout")}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := new(MockStorage)
+			storageMock.On("SaveQuestion", mock.Anything).Return(nil)
+			storageMock.On("GetAllTemplates").Return([]*models.Template{}, nil)
+			storageMock.On("UpdateClassification", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			tg := &telegramtest.Recorder{}
+			bc := &core.BotCore{
+				Messenger: tg,
+				Config:    &config.Config{AdminID: 999999, ClassifyQuestions: true},
+				Storage:   storageMock,
+				LLM:       tt.provider,
+			}
+
+			bot.NewTelegramBotWithCore(bc, nil).HandleMessage(&tgbotapi.Message{
+				Text: "Мне очень плохо, что делать?",
+				From: &tgbotapi.User{ID: 12345},
+				Chat: &tgbotapi.Chat{ID: 12345},
+			})
+
+			// Подтверждение пользователю отправляется до классификации
+			if got := tg.Messages(12345); len(got) != 1 {
+				t.Errorf("Expected confirmation to the sender, got %v", got)
+			}
+			bc.Wait()
+
+			if !tt.classify {
+				storageMock.AssertNotCalled(t, "UpdateClassification", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			storageMock.AssertCalled(t, "UpdateClassification", mock.Anything, "urgent", []string{"здоровье"})
+			if got := tg.Messages(999999); len(got) != 2 {
+				t.Errorf("Expected notification and urgent alert for the admin, got %v", got)
+			}
+		})
+	}
+}
+
+func TestFilterNotConfigured(t *testing.T) {
+	// Без FILTER_RULES_FILE бот получает пустой фильтр от filter.Load("")
+	empty, err := filter.Load("")
+	if err != nil {
+		t.Fatalf("filter.Load failed: %v", err)
+	}
+	telegramBot, tg := newTestBot(new(MockStorage))
+	telegramBot.Core().Filter = empty
+
+	for _, text := range []string{"/testfilter казино", "/reloadfilter"} {
+		telegramBot.HandleMessage(&tgbotapi.Message{
+			Text:     text,
+			From:     &tgbotapi.User{ID: 999999},
+			Chat:     &tgbotapi.Chat{ID: 999999},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(strings.Fields(text)[0])}},
+		})
+	}
+	got := tg.Messages(999999)
+	if len(got) != 2 || !strings.Contains(got[0], "не настроен") || !strings.Contains(got[1], "не настроен") {
+		t.Errorf("Expected both commands to report an unconfigured filter, got %v", got)
+	}
+}
+
+func TestQuestionFilter(t *testing.T) {
+	rules, err := filter.New([]*filter.Rule{
+		{Name: "ads", Words: []string{"казино"}, Action: filter.ActionReject, Message: "Реклама запрещена."},
+		{Name: "links", MaxLinks: 1, Action: filter.ActionHold},
+		{Name: "loans", Words: []string{"кредит"}, Action: filter.ActionReject},
+	})
+	if err != nil {
+		t.Fatalf("filter.New failed: %v", err)
+	}
+
+	storageMock := new(MockStorage)
+	storageMock.On("SaveQuestion", mock.Anything).Return(nil)
+	telegramBot, tg := newTestBot(storageMock)
+	telegramBot.Core().Filter = rules
+
+	// reject: вопрос не сохраняется, пользователь получает сообщение правила
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text: "Лучшее казино", From: &tgbotapi.User{ID: 12345}, Chat: &tgbotapi.Chat{ID: 12345},
+	})
+	storageMock.AssertNotCalled(t, "SaveQuestion", mock.Anything)
+	if got := tg.Messages(12345); len(got) != 1 || got[0] != "Реклама запрещена." {
+		t.Errorf("Expected rejection message, got %v", got)
+	}
+
+	// Правило без сообщения — стандартный ответ на языке отправителя
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text: "Дам кредит", From: &tgbotapi.User{ID: 54321, LanguageCode: "en"}, Chat: &tgbotapi.Chat{ID: 54321},
+	})
+	if got := tg.Messages(54321); len(got) != 1 || got[0] != "Your question cannot be accepted." {
+		t.Errorf("Expected the default rejection in English, got %v", got)
+	}
+
+	// hold: вопрос сохраняется задержанным, админ получает кнопку «Пропустить»
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text: "https://a.ru https://b.ru", From: &tgbotapi.User{ID: 12345}, Chat: &tgbotapi.Chat{ID: 12345},
+	})
+	storageMock.AssertCalled(t, "SaveQuestion", mock.MatchedBy(func(q *models.Question) bool { return q.Held }))
+	if got := tg.Messages(999999); len(got) != 1 || !strings.Contains(got[0], "На проверке") {
+		t.Errorf("Expected held notification for the admin, got %v", got)
+	}
+}
+
+func TestCrisisQuestion(t *testing.T) {
+	detector, err := crisis.New(crisis.Config{
+		DefaultLanguage: "ru",
+		Locales: map[string]crisis.Locale{
+			"ru": {Keywords: []string{"не хочу жить"}, Message: "Телефон доверия: 8-800-2000-122"},
+			"uk": {Keywords: []string{"не хочу жити"}, Message: "Лайфлайн Україна: 7333"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("crisis.New failed: %v", err)
+	}
+	// Фильтр отклонил бы это сообщение, но кризисные сообщения его обходят
+	rules, _ := filter.New([]*filter.Rule{{Name: "all", Regexes: []string{"."}, Action: filter.ActionReject}})
+
+	storageMock := new(MockStorage)
+	storageMock.On("SaveQuestion", mock.Anything).Return(nil)
+	storageMock.On("GetAllTemplates").Return([]*models.Template{}, nil)
+
+	telegramBot, tg := newTestBot(storageMock)
+	bc := telegramBot.Core()
+	bc.Crisis, bc.Filter = detector, rules
+	bc.Config.AdminIDs = []int{555}
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text: "Я не хочу жить",
+		From: &tgbotapi.User{ID: 12345, LanguageCode: "uk"},
+		Chat: &tgbotapi.Chat{ID: 12345},
+	})
+
+	storageMock.AssertCalled(t, "SaveQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		return q.Category == "urgent" && !q.Held
+	}))
+	if got := tg.Messages(12345); len(got) != 2 || got[0] != "Лайфлайн Україна: 7333" {
+		t.Errorf("Expected localised help message first, then confirmation; got %v", got)
+	}
+	for _, admin := range []int64{999999, 555} {
+		got := tg.Messages(admin)
+		if len(got) != 2 || !strings.Contains(got[0], "СРОЧНО") {
+			t.Errorf("Expected urgent alert and notification for admin %d, got %v", admin, got)
+		}
+	}
+}
+
+func TestDigest(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetAllQuestions").Return([]*models.Question{
+		{ID: 1, Text: "Когда сессия?", Tags: []string{"учёба"}, CreatedAt: time.Now()},
+		{ID: 2, Text: "Где расписание?", Tags: []string{"учёба"}, CreatedAt: time.Now()},
+		{ID: 3, Text: "Уже отвечен", Answered: true, CreatedAt: time.Now()},
+		{ID: 4, Text: "Привет", CreatedAt: time.Now()},
+	}, nil)
+
+	provider := &llm.Fake{Responses: []string{"Спрашивают про сессию и расписание."}}
+	telegramBot, tg := newTestBotWithLLM(storageMock, provider)
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/digest 1d",
+		From:     &tgbotapi.User{ID: 999999},
+		Chat:     &tgbotapi.Chat{ID: 999999},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 7}},
+	})
+
+	// По одному запросу к нейросети на каждую тему
+	if prompts := provider.Prompts(); len(prompts) != 2 || !strings.Contains(prompts[0], "#1: Когда сессия?") {
+		t.Fatalf("Expected one prompt per topic, got %v", prompts)
+	}
+
+	got := tg.Messages(999999)
+	if len(got) != 1 {
+		t.Fatalf("Expected a single digest message, got %v", got)
+	}
+	for _, want := range []string{"#учёба (2): #1, #2", "Спрашивают про сессию", "Без темы (1): #4"} {
+		if !strings.Contains(got[0], want) {
+			t.Errorf("Expected digest to contain %q, got %q", want, got[0])
+		}
+	}
+	if strings.Contains(got[0], "#3") {
+		t.Errorf("Answered question must not be in the digest: %q", got[0])
+	}
+	// Индикатор «печатает» отправлен и не считается ошибкой Telegram
+	var out strings.Builder
+	telegramBot.Core().Metrics.Registry().WriteTo(&out)
+	if len(tg.Calls("sendChatAction")) != 1 || strings.Contains(out.String(), `tgbot_telegram_errors_total{method="sendChatAction"}`) {
+		t.Errorf("Expected a single successful typing action, got metrics:\n%s", out.String())
+	}
+}
 
-	// (опционально) Проверяем конкретные поля:
-	// storageMock.AssertCalled(t, "SaveQuestion", &models.Question{
-	// 	UserID:   12345,
-	// 	Username: "testuser",
-	// 	Text:     "Hello world!",
-	// })
+func TestTranslation(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("SaveQuestion", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Question).ID = 5
+	})
+	storageMock.On("GetAllTemplates").Return([]*models.Template{}, nil)
+	storageMock.On("UpdateTranslation", 5, "uk", "[ru] Коли буде сесія?").Return(nil)
+	storageMock.On("GetQuestion", 5).Return(&models.Question{ID: 5, UserID: 12345, Text: "Коли буде сесія?", Language: "uk"}, nil)
+	storageMock.On("CloseQuestion", mock.Anything).Return(nil)
+
+	tg := &telegramtest.Recorder{}
+	translator := &translate.Fake{}
+	bc := &core.BotCore{
+		Messenger:  tg,
+		Config:     &config.Config{AdminID: 999999, TranslateTo: "ru"},
+		Storage:    storageMock,
+		Translator: translator,
+	}
+	telegramBot := bot.NewTelegramBotWithCore(bc, nil)
+
+	// Клиент на английском, но вопрос на украинском — язык берётся из текста
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text: "Коли буде сесія?",
+		From: &tgbotapi.User{ID: 12345, LanguageCode: "en"},
+		Chat: &tgbotapi.Chat{ID: 12345},
+	})
+	bc.Wait()
+
+	storageMock.AssertCalled(t, "UpdateTranslation", 5, "uk", "[ru] Коли буде сесія?")
+	if got := tg.Messages(999999); len(got) != 1 ||
+		!strings.Contains(got[0], "Коли буде сесія?") || !strings.Contains(got[0], "[ru] Коли буде сесія?") {
+		t.Fatalf("Expected original and translation in admin notification, got %v", got)
+	}
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/answer 5 Через неделю",
+		From:     &tgbotapi.User{ID: 999999},
+		Chat:     &tgbotapi.Chat{ID: 999999},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 7}},
+	})
+
+	got := tg.Messages(12345)
+	if len(got) != 2 || !strings.Contains(got[1], "[uk] Через неделю") {
+		t.Errorf("Expected answer translated to the asker's language, got %v", got)
+	}
+	// В базе остаётся исходный ответ администратора
+	storageMock.AssertCalled(t, "CloseQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		return q.Answer == "Через неделю"
+	}))
+}
+
+func TestListSplitsLongOutput(t *testing.T) {
+	var questions []*models.Question
+	for id := 1; id <= 60; id++ {
+		questions = append(questions, &models.Question{
+			ID: id, Username: "u", Text: strings.Repeat("Коли буде сесія? ", 5), Language: "uk",
+			Translation: strings.Repeat("Когда будет сессия? ", 5),
+		})
+	}
+	storageMock := new(MockStorage)
+	storageMock.On("GetAllQuestions").Return(questions, nil)
+	telegramBot, tg := newTestBot(storageMock)
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/list",
+		From:     &tgbotapi.User{ID: 999999},
+		Chat:     &tgbotapi.Chat{ID: 999999},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 5}},
+	})
 
-	// Если хотим проверить точное совпадение, используйте:
-	// storageMock.AssertCalled(t, "SaveQuestion", mock.MatchedBy(func(q *models.Question) bool {
-	// 	return q.UserID == 12345 && q.Username == "testuser" && q.Text == "Hello world!"
-	// }))
+	got := tg.Messages(999999)
+	if len(got) < 2 {
+		t.Fatalf("Expected the list to be split into several messages, got %d", len(got))
+	}
+	for i, part := range got {
+		if n := utf8.RuneCountInString(part); n > core.MaxMessageLength {
+			t.Errorf("Part %d is %d characters long", i, n)
+		}
+	}
+	if all := strings.Join(got, "\n"); !strings.Contains(all, "ID: 1 |") || !strings.Contains(all, "ID: 60 |") {
+		t.Errorf("Expected every question in the split list")
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("SetUserLanguage", 12345, "uk").Return(nil)
+	telegramBot, tg := newTestBot(storageMock)
+
+	command := func(text string, languageCode string) {
+		telegramBot.HandleMessage(&tgbotapi.Message{
+			Text:     text,
+			From:     &tgbotapi.User{ID: 12345, LanguageCode: languageCode},
+			Chat:     &tgbotapi.Chat{ID: 12345},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(strings.Fields(text)[0])}},
+		})
+	}
+
+	// Язык клиента Telegram
+	command("/start", "en-GB")
+	// Неподдерживаемый язык — язык по умолчанию
+	command("/start", "de")
+	command("/language uk", "en")
+	storageMock.AssertCalled(t, "SetUserLanguage", 12345, "uk")
+
+	got := tg.Messages(12345)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 replies, got %v", got)
+	}
+	if !strings.HasPrefix(got[0], "Hi!") || !strings.HasPrefix(got[1], "Привет!") || !strings.Contains(got[2], "Українська") {
+		t.Errorf("Unexpected localized replies: %v", got)
+	}
+
+	// Выбранный язык важнее языка клиента
+	storageMock.On("GetUserLanguage", 12345).Return("uk", nil)
+	command("/start", "en")
+	if got := tg.Messages(12345); !strings.HasPrefix(got[3], "Привіт!") {
+		t.Errorf("Expected Ukrainian greeting, got %q", got[3])
+	}
+}
+
+func TestHelpShowsAdminCommandsToAdmins(t *testing.T) {
+	telegramBot, tg := newTestBot(new(MockStorage))
+
+	for _, id := range []int64{12345, 999999} {
+		telegramBot.HandleMessage(&tgbotapi.Message{
+			Text:     "/help",
+			From:     &tgbotapi.User{ID: id},
+			Chat:     &tgbotapi.Chat{ID: id},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 5}},
+		})
+	}
+
+	if got := tg.Messages(12345); len(got) != 1 || strings.Contains(got[0], "/answer") {
+		t.Errorf("Regular users must not see admin commands, got %v", got)
+	}
+	if got := tg.Messages(999999); len(got) != 1 || !strings.Contains(got[0], "/answer") {
+		t.Errorf("Admins must see admin commands, got %v", got)
+	}
+}
+
+func TestBan(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetQuestion", 7).Return(&models.Question{ID: 7, UserID: 12345}, nil)
+	storageMock.On("BanUser", 12345, "спам").Return(nil)
+	storageMock.On("UnbanUser", 12345).Return(true, nil)
+	telegramBot, tg := newTestBot(storageMock)
+
+	command := func(from int64, text string) {
+		telegramBot.HandleMessage(&tgbotapi.Message{
+			Text:     text,
+			From:     &tgbotapi.User{ID: from},
+			Chat:     &tgbotapi.Chat{ID: from},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(strings.Fields(text)[0])}},
+		})
+	}
+
+	// Пользователь не может банить
+	command(12345, "/ban 7")
+	storageMock.AssertNotCalled(t, "BanUser", mock.Anything, mock.Anything)
+
+	command(999999, "/ban 7 спам")
+	command(999999, "/unban 7")
+	storageMock.AssertCalled(t, "BanUser", 12345, "спам")
+	storageMock.AssertCalled(t, "UnbanUser", 12345)
+	if got := tg.Messages(999999); len(got) != 2 || !strings.Contains(got[0], "забанен") || !strings.Contains(got[1], "разбанен") {
+		t.Errorf("Unexpected admin replies: %v", got)
+	}
+
+	// Забаненный пользователь не может ни задать вопрос, ни вызвать команду
+	storageMock.On("IsBanned", 12345).Return(true, nil)
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text: "Вопрос", From: &tgbotapi.User{ID: 12345}, Chat: &tgbotapi.Chat{ID: 12345},
+	})
+	command(12345, "/start")
+	storageMock.AssertNotCalled(t, "SaveQuestion", mock.Anything)
+	got := tg.Messages(12345)
+	if len(got) != 3 || !strings.Contains(got[0], "нет доступа") || !strings.Contains(got[1], "не можете") || !strings.Contains(got[2], "не можете") {
+		t.Errorf("Unexpected replies to the banned user: %v", got)
+	}
+}
+
+func TestRejectAndTag(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetQuestion", 7).Return(&models.Question{ID: 7, UserID: 12345, Category: "normal"}, nil).Once()
+	storageMock.On("GetQuestion", 7).Return(&models.Question{ID: 7, UserID: 12345, Rejected: true}, nil)
+	storageMock.On("CloseQuestion", mock.Anything).Return(nil)
+	storageMock.On("UpdateClassification", 7, "", []string{"учёба", "сессия"}).Return(nil)
+	telegramBot, tg := newTestBot(storageMock)
+
+	command := func(text string) {
+		telegramBot.HandleMessage(&tgbotapi.Message{
+			Text:     text,
+			From:     &tgbotapi.User{ID: 999999},
+			Chat:     &tgbotapi.Chat{ID: 999999},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(strings.Fields(text)[0])}},
+		})
+	}
+
+	command("/reject 7 не по теме")
+	storageMock.AssertCalled(t, "CloseQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		return q.Rejected && !q.Answered && q.Answer == "не по теме"
+	}))
+	if got := tg.Messages(12345); len(got) != 1 || !strings.Contains(got[0], "отклонён") || !strings.Contains(got[0], "не по теме") {
+		t.Errorf("Expected the asker to be notified with the reason, got %v", got)
+	}
+
+	// Отклонённый вопрос нельзя ни отклонить снова, ни ответить на него
+	command("/reject 7")
+	command("/answer 7 Поздно")
+	command("/tag 7 #Учёба, сессия учёба")
+	storageMock.AssertNumberOfCalls(t, "CloseQuestion", 1)
+	got := tg.Messages(999999)
+	if len(got) != 4 || !strings.Contains(got[1], "уже отклонён") || !strings.Contains(got[2], "уже отклонён") || !strings.Contains(got[3], "#учёба #сессия") {
+		t.Errorf("Unexpected admin replies: %v", got)
+	}
+	if got := tg.Messages(12345); len(got) != 1 {
+		t.Errorf("Expected no more messages to the asker, got %v", got)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	telegramBot, tg := newTestBot(new(MockStorage))
+	telegramBot.Core().Config.RateLimit = 1
+	telegramBot.Core().Config.RateLimitBurst = 2
+	// Лимит задаётся при регистрации хендлеров
+	telegramBot = bot.NewTelegramBotWithCore(telegramBot.Core(), nil)
+
+	for _, id := range []int64{12345, 999999} {
+		for i := 0; i < 4; i++ {
+			telegramBot.HandleMessage(&tgbotapi.Message{
+				Text:     "/start",
+				From:     &tgbotapi.User{ID: id},
+				Chat:     &tgbotapi.Chat{ID: id},
+				Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 6}},
+			})
+		}
+	}
+
+	// Два приветствия и одно предупреждение; администратора лимит не касается
+	got := tg.Messages(12345)
+	if len(got) != 3 || !strings.Contains(got[2], "Слишком много") {
+		t.Errorf("Expected two greetings and a single warning, got %v", got)
+	}
+	if got := tg.Messages(999999); len(got) != 4 {
+		t.Errorf("Expected admin not to be rate limited, got %v", got)
+	}
+	var out strings.Builder
+	telegramBot.Core().Metrics.Registry().WriteTo(&out)
+	for _, want := range []string{
+		`tgbot_commands_total{command="start",outcome="ok"} 6`,
+		`tgbot_commands_total{command="start",outcome="rate_limited"} 2`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %s in metrics, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPanicInHandlerIsRecovered(t *testing.T) {
+	// Вызов мока без ожидания паникует — как ошибка программиста в хендлере
+	telegramBot, tg := newTestBot(new(MockStorage))
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text: "Вопрос", From: &tgbotapi.User{ID: 12345}, Chat: &tgbotapi.Chat{ID: 12345},
+	})
+
+	if got := tg.Messages(12345); len(got) != 1 || !strings.Contains(got[0], "внутренняя ошибка") {
+		t.Errorf("Expected internal error reply, got %v", got)
+	}
+	var out strings.Builder
+	telegramBot.Core().Metrics.Registry().WriteTo(&out)
+	if want := `tgbot_commands_total{command="message",outcome="panic"} 1`; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %s in metrics, got:\n%s", want, out.String())
+	}
+}
+
+func TestPanicInCallbackIsRecovered(t *testing.T) {
+	// Черновика нет в ожиданиях мока — GetDraft паникует внутри обработки кнопки
+	storageMock := new(MockStorage)
+	storageMock.On("SetLastUpdateID", 1).Return(nil)
+	telegramBot, tg := newTestBot(storageMock)
+
+	body := `{"update_id": 1, "callback_query": {"id": "cb1", "data": "sug:send:1",
+		"from": {"id": 999999}, "message": {"message_id": 5, "chat": {"id": 999999}}}}`
+	rec := httptest.NewRecorder()
+	telegramBot.WebhookHandler("").ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	telegramBot.Shutdown(time.Second)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the update to be acknowledged, got %d", rec.Code)
+	}
+	if got := tg.Calls("answerCallbackQuery"); len(got) != 1 || !strings.Contains(got[0].Text, "внутренняя ошибка") {
+		t.Errorf("Expected internal error answer to the button, got %v", got)
+	}
+	// Обновление всё равно отмечено обработанным, а паника учтена в метриках
+	storageMock.AssertCalled(t, "SetLastUpdateID", 1)
+	var out strings.Builder
+	telegramBot.Core().Metrics.Registry().WriteTo(&out)
+	if want := `tgbot_commands_total{command="callback:sug",outcome="panic"} 1`; !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %s in metrics, got:\n%s", want, out.String())
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	spec := []handlers.Arg{{Name: "id", Type: handlers.ArgInt}, {Name: "name", Optional: true}, {Name: "text", Type: handlers.ArgText, Optional: true}}
+
+	tests := []struct {
+		input   string
+		want    handlers.Args
+		wantErr bool
+	}{
+		{input: "7", want: handlers.Args{"id": 7}},
+		{input: " 7  #имя  текст\nв две строки ", want: handlers.Args{"id": 7, "name": "#имя", "text": "текст\nв две строки"}},
+		{input: "", wantErr: true},
+		{input: "семь", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := handlers.ParseArgs(spec, tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseArgs(%q): unexpected error %v", tt.input, err)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseArgs(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := handlers.ParseArgs([]handlers.Arg{{Name: "id", Type: handlers.ArgInt}}, "7 8"); err == nil {
+		t.Errorf("Expected extra arguments to be rejected")
+	}
+}
+
+func TestCommandUsageErrors(t *testing.T) {
+	telegramBot, tg := newTestBot(new(MockStorage))
+
+	for _, text := range []string{"/answer", "/answer семь привет", "/askcohere"} {
+		telegramBot.HandleMessage(&tgbotapi.Message{
+			Text:     text,
+			From:     &tgbotapi.User{ID: 999999},
+			Chat:     &tgbotapi.Chat{ID: 999999},
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(strings.Fields(text)[0])}},
+		})
+	}
+
+	got := tg.Messages(999999)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 replies, got %v", got)
+	}
+	if !strings.Contains(got[0], "/answer <id> <ответ>") {
+		t.Errorf("Expected generated usage, got %q", got[0])
+	}
+	if !strings.Contains(got[1], "«семь» — не число") {
+		t.Errorf("Expected number error, got %q", got[1])
+	}
+	// Псевдоним ведёт к той же команде, подсказка — с основным именем
+	if !strings.Contains(got[2], "/ask <текст>") {
+		t.Errorf("Expected /ask usage for the alias, got %q", got[2])
+	}
+}
+
+func TestCommandsAreDocumented(t *testing.T) {
+	telegramBot, _ := newTestBot(new(MockStorage))
+	catalog := telegramBot.Core().Catalog()
+
+	for _, lang := range catalog.Languages() {
+		keys := make(map[string]bool)
+		for _, key := range catalog.Keys(lang) {
+			keys[key] = true
+		}
+		for _, cmd := range telegramBot.Commands().Commands(core.RoleAdmin) {
+			if !keys["cmd."+cmd.Name] {
+				t.Errorf("%s: no description cmd.%s", lang, cmd.Name)
+			}
+			for _, arg := range cmd.Args {
+				if !keys["arg."+arg.Name] {
+					t.Errorf("%s: no argument name arg.%s", lang, arg.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestMediaCommand(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("GetQuestion", 3).Return(&models.Question{ID: 3, Text: "Что на фото?", FileID: "photo-1", MediaType: "photo"}, nil)
+	telegramBot, tg := newTestBot(storageMock)
+
+	telegramBot.HandleMessage(&tgbotapi.Message{
+		Text:     "/media 3",
+		From:     &tgbotapi.User{ID: 999999},
+		Chat:     &tgbotapi.Chat{ID: 999999},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 6}},
+	})
+
+	media := tg.Media(999999)
+	if len(media) != 1 || media[0].Method != "sendPhoto" || media[0].FileID != "photo-1" || media[0].Text != "Вопрос #3: Что на фото?" {
+		t.Fatalf("Expected the question photo with caption, got %+v", media)
+	}
+	if got := tg.Messages(999999); len(got) != 0 {
+		t.Errorf("Expected no text messages, got %v", got)
+	}
 }
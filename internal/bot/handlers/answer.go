@@ -1,61 +1,78 @@
 package handlers
 
 import (
-	"fmt"
-	"strconv"
+	"context"
+	"errors"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/storage"
 )
 
 type AnswerHandler struct {
 	Core *core.BotCore
 }
 
-func (h *AnswerHandler) CanHandle(cmd string) bool {
-	return cmd == "answer"
+func (h *AnswerHandler) Commands() []Command {
+	return []Command{{
+		Name: "answer",
+		Role: core.RoleAdmin,
+		Args: []Arg{{Name: "id", Type: ArgInt}, {Name: "answer", Type: ArgText}},
+		Run:  h.answer,
+	}}
 }
 
-func (h *AnswerHandler) Handle(msg *tgbotapi.Message) {
-	// Проверка прав (админ)
-	if int(msg.From.ID) != h.Core.Config.AdminID {
-		h.Core.SendMessage(msg.Chat.ID, "У вас нет доступа к этой команде.")
+func (h *AnswerHandler) answer(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	qID := args.Int("id")
+	answerText := args.String("answer")
+	q, err := h.Core.Storage.GetQuestion(qID)
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.question_not_found", i18n.Params{"error": err})
 		return
 	}
-
-	args := strings.SplitN(msg.Text, " ", 3)
-	if len(args) < 3 {
-		h.Core.SendMessage(msg.Chat.ID, "Использование: /answer <id> <ответ>")
+	if err := core.CheckOpen(q); err != nil {
+		h.Core.Reply(ctx, msg, closedKey(err))
 		return
 	}
 
-	qID, err := strconv.Atoi(args[1])
-	if err != nil {
-		h.Core.SendMessage(msg.Chat.ID, "Неверный ID вопроса.")
-		return
+	// "/answer <id> #имя" — подставить сохранённый шаблон
+	if name, ok := strings.CutPrefix(answerText, "#"); ok && !strings.ContainsAny(name, " \n") {
+		t, err := h.Core.Storage.GetTemplateByName(name)
+		if errors.Is(err, storage.ErrTemplateNotFound) {
+			h.Core.Reply(ctx, msg, "template.unknown", i18n.Params{"name": name})
+			return
+		}
+		if err != nil {
+			h.Core.Reply(ctx, msg, "template.not_found", i18n.Params{"error": err})
+			return
+		}
+		if answerText, err = RenderTemplate(t, q, time.Now()); err != nil {
+			h.Core.Reply(ctx, msg, "template.invalid", i18n.Params{"name": t.Name, "error": err})
+			return
+		}
 	}
-	answerText := args[2]
 
-	q, err := h.Core.Storage.GetQuestion(qID)
-	if err != nil {
-		h.Core.SendMessage(msg.Chat.ID, "Вопрос не найден: "+err.Error())
+	err = h.Core.AnswerQuestion(ctx, q, answerText)
+	switch {
+	case errors.Is(err, core.ErrAlreadyAnswered), errors.Is(err, core.ErrRejected):
+		h.Core.Reply(ctx, msg, closedKey(err))
 		return
-	}
-	if q.Answered {
-		h.Core.SendMessage(msg.Chat.ID, "На этот вопрос уже был дан ответ.")
+	case err != nil:
+		h.Core.Reply(ctx, msg, "common.update_failed", i18n.Params{"error": err})
 		return
 	}
 
-	resp := fmt.Sprintf("Ответ на ваш вопрос (ID=%d):\n%s", qID, answerText)
-	h.Core.SendMessage(int64(q.UserID), resp)
+	h.Core.Reply(ctx, msg, "common.answer_sent", i18n.Params{"id": qID})
+}
 
-	q.Answered = true
-	q.Answer = answerText
-	if err := h.Core.Storage.UpdateQuestion(q); err != nil {
-		h.Core.SendMessage(msg.Chat.ID, "Ошибка при обновлении вопроса: "+err.Error())
-		return
+// closedKey возвращает ключ ответа администратору для ошибки core.CheckOpen
+// (или AnswerQuestion, если вопрос успели закрыть одновременно).
+func closedKey(err error) string {
+	if errors.Is(err, core.ErrRejected) {
+		return "common.already_rejected"
 	}
-
-	h.Core.SendMessage(msg.Chat.ID, fmt.Sprintf("Ответ для вопроса %d отправлен.", qID))
+	return "common.already_answered"
 }
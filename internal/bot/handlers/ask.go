@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/llm"
+	"telegram-anonymous-bot/internal/models"
+)
+
+// askSystemPrompt задаёт поведение нейросети в диалоге /ask.
+const askSystemPrompt = "Ты — дружелюбный помощник в Telegram. Отвечай кратко и по делу, на языке собеседника."
+
+// AskHandler обрабатывает команду /ask — диалог с настроенной нейросетью.
+// История хранится в базе по пользователю, /newchat начинает диалог заново.
+// /askcohere оставлена как синоним для старых клиентов.
+type AskHandler struct {
+	Core *core.BotCore
+}
+
+func (h *AskHandler) Commands() []Command {
+	return []Command{{
+		Name:    "ask",
+		Aliases: []string{"askcohere"},
+		Args:    []Arg{{Name: "text", Type: ArgText}},
+		Run:     h.ask,
+	}}
+}
+
+func (h *AskHandler) ask(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	userInput := args.String("text")
+
+	userID := int(msg.From.ID)
+	history, err := h.history(userID)
+	if err != nil {
+		h.Core.Reply(ctx, msg, "ask.history_failed", i18n.Params{"error": err})
+		return
+	}
+
+	messages := []llm.Message{{Role: llm.RoleSystem, Content: askSystemPrompt}}
+	for _, m := range history {
+		messages = append(messages, llm.Message{Role: m.Role, Content: m.Content})
+	}
+	messages = append(messages, llm.Message{Role: llm.RoleUser, Content: userInput})
+	messages = llm.TrimToBudget(messages, h.Core.Config.ChatHistoryTokens)
+
+	// Ответ и ошибки StreamChat показывает пользователю сам
+	answer, err := h.Core.StreamChat(ctx, msg.Chat.ID, h.Core.Lang(ctx, msg.From), messages)
+	if err != nil {
+		return
+	}
+
+	for _, m := range []*models.ChatMessage{
+		{UserID: userID, Role: llm.RoleUser, Content: userInput},
+		{UserID: userID, Role: llm.RoleAssistant, Content: answer},
+	} {
+		if err := h.Core.Storage.AddChatMessage(m); err != nil {
+			h.Core.Reply(ctx, msg, "ask.save_failed", i18n.Params{"error": err})
+			return
+		}
+	}
+}
+
+// history возвращает сохранённые реплики пользователя. Если диалог простаивал дольше
+// ChatSessionTTL, история очищается и разговор начинается заново.
+func (h *AskHandler) history(userID int) ([]*models.ChatMessage, error) {
+	history, err := h.Core.Storage.GetChatMessages(userID, h.Core.Config.ChatHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := h.Core.Config.ChatSessionTTL
+	if ttl > 0 && len(history) > 0 && time.Since(history[len(history)-1].CreatedAt) > ttl {
+		if err := h.Core.Storage.ClearChat(userID); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return history, nil
+}
+
+// NewChatHandler обрабатывает команду /newchat — сброс истории диалога с нейросетью.
+type NewChatHandler struct {
+	Core *core.BotCore
+}
+
+func (h *NewChatHandler) Commands() []Command {
+	return []Command{{Name: "newchat", Run: h.newChat}}
+}
+
+func (h *NewChatHandler) newChat(ctx context.Context, msg *tgbotapi.Message, _ Args) {
+	if err := h.Core.Storage.ClearChat(int(msg.From.ID)); err != nil {
+		h.Core.Reply(ctx, msg, "newchat.failed", i18n.Params{"error": err})
+		return
+	}
+	h.Core.Reply(ctx, msg, "newchat.done")
+}
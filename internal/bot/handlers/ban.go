@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+)
+
+// BanHandler обрабатывает команды /ban <id вопроса> [причина] и /unban <id вопроса>.
+// Администратор не знает автора вопроса, поэтому бан выдаётся по ID вопроса.
+type BanHandler struct {
+	Core *core.BotCore
+}
+
+func (h *BanHandler) Commands() []Command {
+	return []Command{
+		{
+			Name: "ban",
+			Role: core.RoleAdmin,
+			Args: []Arg{{Name: "id", Type: ArgInt}, {Name: "reason", Type: ArgText, Optional: true}},
+			Run:  h.ban,
+		},
+		{Name: "unban", Role: core.RoleAdmin, Args: []Arg{{Name: "id", Type: ArgInt}}, Run: h.unban},
+	}
+}
+
+func (h *BanHandler) ban(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	q, err := h.Core.Storage.GetQuestion(args.Int("id"))
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.question_not_found", i18n.Params{"error": err})
+		return
+	}
+	if h.Core.IsAdmin(int64(q.UserID)) {
+		h.Core.Reply(ctx, msg, "ban.admin")
+		return
+	}
+	if err := h.Core.Storage.BanUser(q.UserID, args.String("reason")); err != nil {
+		h.Core.Reply(ctx, msg, "ban.failed", i18n.Params{"error": err})
+		return
+	}
+	h.Core.Reply(ctx, msg, "ban.done", i18n.Params{"id": q.ID})
+}
+
+func (h *BanHandler) unban(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	q, err := h.Core.Storage.GetQuestion(args.Int("id"))
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.question_not_found", i18n.Params{"error": err})
+		return
+	}
+
+	removed, err := h.Core.Storage.UnbanUser(q.UserID)
+	switch {
+	case err != nil:
+		h.Core.Reply(ctx, msg, "ban.failed", i18n.Params{"error": err})
+	case removed:
+		h.Core.Reply(ctx, msg, "ban.removed", i18n.Params{"id": q.ID})
+	default:
+		h.Core.Reply(ctx, msg, "ban.not_banned", i18n.Params{"id": q.ID})
+	}
+}
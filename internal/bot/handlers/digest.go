@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/digest"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/moderation"
+)
+
+// DigestHandler обрабатывает команду /digest [период] — сводку неотвеченных вопросов по темам.
+type DigestHandler struct {
+	Core *core.BotCore
+}
+
+func (h *DigestHandler) Commands() []Command {
+	return []Command{{
+		Name: "digest",
+		Role: core.RoleAdmin,
+		Args: []Arg{{Name: "period", Optional: true}},
+		Run:  h.digest,
+	}}
+}
+
+func (h *DigestHandler) digest(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	since, err := digest.ParseSince(args.String("period"), time.Now())
+	if err != nil {
+		h.Core.Reply(ctx, msg, "digest.usage")
+		return
+	}
+
+	h.Core.Request(ctx, tgbotapi.NewChatAction(msg.Chat.ID, tgbotapi.ChatTyping))
+	report, err := h.Report(ctx, since, h.Core.Lang(ctx, msg.From))
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.list_failed", i18n.Params{"error": err})
+		return
+	}
+	for _, part := range core.SplitMessage(report, core.MaxMessageLength) {
+		h.Core.SendMessage(ctx, msg.Chat.ID, part)
+	}
+}
+
+// Report собирает сводку неотвеченных вопросов, заданных не раньше since, на языке lang.
+// Каждая тема пересказывается нейросетью по частям, чтобы не превышать лимиты провайдера;
+// если нейросеть недоступна, в сводке остаются только темы и номера вопросов.
+func (h *DigestHandler) Report(ctx context.Context, since time.Time, lang string) (string, error) {
+	questions, err := h.Core.Storage.GetAllQuestions()
+	if err != nil {
+		return "", err
+	}
+
+	groups := digest.GroupUnanswered(questions, since)
+	if len(groups) == 0 {
+		return h.Core.T(lang, "digest.empty"), nil
+	}
+
+	total := 0
+	for _, g := range groups {
+		total += len(g.Questions)
+	}
+
+	var b strings.Builder
+	if since.IsZero() {
+		b.WriteString(h.Core.N(lang, "digest.header", total))
+	} else {
+		b.WriteString(h.Core.N(lang, "digest.header_since", total, i18n.Params{"since": since.Format("02.01.2006 15:04")}))
+	}
+	b.WriteString("\n")
+
+	for _, g := range groups {
+		fmt.Fprintf(&b, "\n%s (%d): %s\n", h.topicTitle(lang, g.Topic), len(g.Questions), formatIDs(g.IDs()))
+		b.WriteString(h.summarize(ctx, lang, g))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// summarize пересказывает группу нейросетью, по одному запросу на каждую часть.
+func (h *DigestHandler) summarize(ctx context.Context, lang string, g digest.Group) string {
+	var summaries []string
+	for _, chunk := range digest.Chunks(g, h.Core.Config.DigestChunkChars) {
+		text, err := h.Core.Generate(ctx, digest.Prompt(g.Topic, chunk, lang))
+		if err != nil {
+			slog.ErrorContext(ctx, "Не удалось пересказать тему дайджеста", "topic", g.Topic, "error", err)
+			return h.Core.T(lang, "digest.unavailable", i18n.Params{"error": err})
+		}
+		summaries = append(summaries, strings.TrimSpace(text))
+	}
+	return strings.Join(summaries, "\n")
+}
+
+func (h *DigestHandler) topicTitle(lang, topic string) string {
+	switch topic {
+	case digest.NoTopic:
+		return h.Core.T(lang, "digest.no_topic")
+	case moderation.CategoryUrgent, moderation.CategorySpam, moderation.CategoryAbusive:
+		return strings.ToUpper(topic)
+	default:
+		return "#" + topic
+	}
+}
+
+func formatIDs(ids []int) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, "#"+strconv.Itoa(id))
+	}
+	return strings.Join(parts, ", ")
+}
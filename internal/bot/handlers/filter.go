@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/filter"
+	"telegram-anonymous-bot/internal/i18n"
+)
+
+// releaseCallbackPrefix — префикс данных кнопки «Пропустить»: "rel:<id вопроса>"
+const releaseCallbackPrefix = "rel:"
+
+// FilterHandler обрабатывает команды /testfilter <текст> и /reloadfilter
+type FilterHandler struct {
+	Core *core.BotCore
+}
+
+func (h *FilterHandler) Commands() []Command {
+	return []Command{
+		{Name: "testfilter", Role: core.RoleAdmin, Args: []Arg{{Name: "text", Type: ArgText}}, Run: h.test},
+		{Name: "reloadfilter", Role: core.RoleAdmin, Run: h.reload},
+	}
+}
+
+func (h *FilterHandler) test(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	if h.Core.Filter == nil || !h.Core.Filter.Configured() {
+		h.Core.Reply(ctx, msg, "filter.not_configured")
+		return
+	}
+	h.Core.SendMessage(ctx, msg.Chat.ID, describeDecision(h.Core, h.Core.Lang(ctx, msg.From), h.Core.Filter.Check(args.String("text"))))
+}
+
+func (h *FilterHandler) reload(ctx context.Context, msg *tgbotapi.Message, _ Args) {
+	if h.Core.Filter == nil || !h.Core.Filter.Configured() {
+		h.Core.Reply(ctx, msg, "filter.not_configured")
+		return
+	}
+	if err := h.Core.Filter.Reload(); err != nil {
+		h.Core.Reply(ctx, msg, "filter.reload_failed", i18n.Params{"error": err})
+		return
+	}
+	h.Core.SendMessage(ctx, msg.Chat.ID, h.Core.N(h.Core.Lang(ctx, msg.From), "filter.reloaded", h.Core.Filter.Len()))
+}
+
+func describeDecision(bc *core.BotCore, lang string, d filter.Decision) string {
+	if len(d.Rules) == 0 {
+		return bc.T(lang, "filter.no_match")
+	}
+
+	// Ключи действий: filter.action.tag, filter.action.hold, filter.action.reject, filter.action.drop
+	message := d.Message
+	if message == "" {
+		message = bc.T(lang, "question.rejected")
+	}
+	action := bc.T(lang, "filter.action."+string(d.Action), i18n.Params{"message": message})
+	result := bc.T(lang, "filter.decision", i18n.Params{"rules": strings.Join(d.Rules, ", "), "action": action})
+	if len(d.Tags) > 0 {
+		result += bc.T(lang, "filter.tags", i18n.Params{"tags": strings.Join(d.Tags, " #")})
+	}
+	return result
+}
+
+func releaseKeyboard(bc *core.BotCore, lang string, questionID int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(bc.T(lang, "filter.button_release"),
+			fmt.Sprintf("%s%d", releaseCallbackPrefix, questionID)),
+	))
+}
+
+// ReleaseCallbackHandler снимает задержку фильтра с вопроса по кнопке «Пропустить».
+type ReleaseCallbackHandler struct {
+	Core *core.BotCore
+}
+
+func (h *ReleaseCallbackHandler) CanHandleCallback(data string) bool {
+	return strings.HasPrefix(data, releaseCallbackPrefix)
+}
+
+func (h *ReleaseCallbackHandler) HandleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if !h.Core.IsAdmin(cb.From.ID) {
+		h.Core.AnswerCallback(ctx, cb, "common.no_access")
+		return
+	}
+
+	qID, err := strconv.Atoi(strings.TrimPrefix(cb.Data, releaseCallbackPrefix))
+	if err != nil {
+		h.Core.AnswerCallback(ctx, cb, "common.bad_callback")
+		return
+	}
+	q, err := h.Core.Storage.GetQuestion(qID)
+	if err != nil {
+		h.Core.AnswerCallback(ctx, cb, "common.question_not_found", i18n.Params{"error": err})
+		return
+	}
+	if err := h.Core.Storage.SetHeld(q.ID, false); err != nil {
+		h.Core.AnswerCallback(ctx, cb, "common.update_failed", i18n.Params{"error": err})
+		return
+	}
+	h.Core.AnswerCallback(ctx, cb, "filter.released", i18n.Params{"id": q.ID})
+
+	if cb.Message == nil {
+		return
+	}
+	// Заменяем уведомление обычным — с кнопками шаблонов
+	edit := tgbotapi.NewEditMessageText(cb.Message.Chat.ID, cb.Message.MessageID,
+		questionNotificationText(h.Core, h.Core.Lang(ctx, cb.From), q))
+	if templates, err := h.Core.Storage.GetAllTemplates(); err == nil && len(templates) > 0 {
+		markup := templateKeyboard(q.ID, templates)
+		edit.ReplyMarkup = &markup
+	}
+	h.Core.Request(ctx, edit)
+}
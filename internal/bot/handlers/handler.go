@@ -1,8 +1,50 @@
 package handlers
 
-import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+import (
+	"context"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// CommandHandler объявляет обрабатываемые команды; регистрирует их TelegramBot.
 type CommandHandler interface {
-	CanHandle(cmd string) bool
-	Handle(msg *tgbotapi.Message)
+	Commands() []Command
+}
+
+// CallbackHandler обрабатывает нажатия на inline-кнопки.
+// Данные кнопки имеют вид "<префикс>:<аргументы>", по префиксу выбирается хендлер.
+type CallbackHandler interface {
+	CanHandleCallback(data string) bool
+	HandleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery)
+}
+
+// HandlerFunc — обработка одного сообщения. ctx несёт атрибуты обновления для журнала
+// (logger.With) и передаётся во все вызовы BotCore.
+type HandlerFunc func(ctx context.Context, msg *tgbotapi.Message)
+
+// Middleware оборачивает обработку сообщения общей логикой: проверкой прав,
+// логированием, ограничением частоты и т.п.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Wrap оборачивает обработку цепочкой middleware; первая в списке выполняется первой.
+func Wrap(handle HandlerFunc, mws ...Middleware) HandlerFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handle = mws[i](handle)
+	}
+	return handle
+}
+
+// CallbackFunc — обработка одного нажатия на inline-кнопку.
+type CallbackFunc func(ctx context.Context, cb *tgbotapi.CallbackQuery)
+
+// CallbackMiddleware — то же, что Middleware, для нажатий на кнопки.
+type CallbackMiddleware func(next CallbackFunc) CallbackFunc
+
+// WrapCallback оборачивает обработку нажатий цепочкой middleware; первая в списке
+// выполняется первой.
+func WrapCallback(handle CallbackFunc, mws ...CallbackMiddleware) CallbackFunc {
+	for i := len(mws) - 1; i >= 0; i-- {
+		handle = mws[i](handle)
+	}
+	return handle
 }
@@ -1,31 +1,49 @@
 package handlers
 
 import (
+	"context"
+	"strings"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"telegram-anonymous-bot/internal/bot/core"
 )
 
-// HelpHandler обрабатывает команду /help
+// helpHeaders — заголовки разделов справки по ролям.
+var helpHeaders = map[core.Role]string{
+	core.RoleUser:  "help.header",
+	core.RoleAdmin: "help.admin_header",
+}
+
+// HelpHandler обрабатывает команду /help: справка строится по реестру команд,
+// каждый видит только доступные его роли команды.
 type HelpHandler struct {
-	Core *core.BotCore
+	Core     *core.BotCore
+	Registry *Registry
+}
+
+func (h *HelpHandler) Commands() []Command {
+	return []Command{{Name: "help", Run: h.help}}
 }
 
-// CanHandle проверяет, является ли команда — "help"
-func (h *HelpHandler) CanHandle(cmd string) bool {
-	return cmd == "help"
+func (h *HelpHandler) help(ctx context.Context, msg *tgbotapi.Message, _ Args) {
+	lang := h.Core.Lang(ctx, msg.From)
+	h.Core.SendMessage(ctx, msg.Chat.ID, h.text(lang, h.Core.Role(msg.From.ID)))
 }
 
-// Handle реализует логику для /help
-func (h *HelpHandler) Handle(msg *tgbotapi.Message) {
-	// Можно отправить краткую справку по основным командам
-	helpText := `Доступные команды:
-    
-/start — начало работы
-/list  — список всех вопросов (админ)
-/answer <id> <ответ> — ответ на вопрос (админ)
-/media <id> — показать фото/видео (админ)
-/askcohere <текст> — спросить Cohere AI
-/help — показать эту справку
-`
-	h.Core.SendMessage(msg.Chat.ID, helpText)
+// text возвращает справку на языке lang для роли role.
+func (h *HelpHandler) text(lang string, role core.Role) string {
+	var b strings.Builder
+	for r := core.RoleUser; r <= role; r++ {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(h.Core.T(lang, helpHeaders[r]))
+		b.WriteString("\n")
+		for _, cmd := range h.Registry.Commands(role) {
+			if cmd.Role == r {
+				b.WriteString(Usage(h.Core, lang, cmd) + " — " + h.Core.T(lang, "cmd."+cmd.Name) + "\n")
+			}
+		}
+	}
+	return b.String()
 }
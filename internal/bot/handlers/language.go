@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+)
+
+// LanguageHandler обрабатывает команду /language [код|auto] — выбор языка бота.
+// Без аргумента показывает текущий язык, "auto" возвращает язык клиента Telegram.
+// Администратору меню команд переводится на новый язык сразу.
+type LanguageHandler struct {
+	Core     *core.BotCore
+	Registry *Registry
+}
+
+func (h *LanguageHandler) Commands() []Command {
+	return []Command{{
+		Name:    "language",
+		Aliases: []string{"lang"},
+		Args:    []Arg{{Name: "code", Optional: true}},
+		Run:     h.language,
+	}}
+}
+
+func (h *LanguageHandler) language(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	catalog := h.Core.Catalog()
+	languages := strings.Join(catalog.Languages(), ", ")
+
+	arg := strings.ToLower(args.String("code"))
+	switch {
+	case arg == "":
+		lang := h.Core.Lang(ctx, msg.From)
+		h.Core.Reply(ctx, msg, "language.current", i18n.Params{"name": h.Core.T(lang, "language.name"), "languages": languages})
+	case arg == "auto":
+		if err := h.Core.Storage.SetUserLanguage(int(msg.From.ID), ""); err != nil {
+			h.Core.Reply(ctx, msg, "language.save_failed", i18n.Params{"error": err})
+			return
+		}
+		h.Core.Reply(ctx, msg, "language.reset")
+		h.refreshMenu(ctx, msg.From)
+	case catalog.Supports(arg):
+		if err := h.Core.Storage.SetUserLanguage(int(msg.From.ID), arg); err != nil {
+			h.Core.Reply(ctx, msg, "language.save_failed", i18n.Params{"error": err})
+			return
+		}
+		// Подтверждение уже на новом языке
+		h.Core.Reply(ctx, msg, "language.set", i18n.Params{"name": h.Core.T(arg, "language.name")})
+		h.refreshMenu(ctx, msg.From)
+	default:
+		h.Core.Reply(ctx, msg, "language.unknown", i18n.Params{"lang": arg, "languages": languages})
+	}
+}
+
+// refreshMenu публикует меню администратора на его текущем языке.
+func (h *LanguageHandler) refreshMenu(ctx context.Context, u *tgbotapi.User) {
+	if h.Registry != nil && h.Core.IsAdmin(u.ID) {
+		h.Core.Request(ctx, h.Registry.AdminMenu(u.ID, h.Core.Lang(ctx, u)))
+	}
+}
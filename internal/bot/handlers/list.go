@@ -1,41 +1,79 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
+	"sort"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/moderation"
 )
 
 type ListHandler struct {
 	Core *core.BotCore
 }
 
-func (h *ListHandler) CanHandle(cmd string) bool {
-	return cmd == "list"
+func (h *ListHandler) Commands() []Command {
+	return []Command{{Name: "list", Role: core.RoleAdmin, Run: h.list}}
 }
 
-func (h *ListHandler) Handle(msg *tgbotapi.Message) {
-	// Проверка прав (админ)
-	if int(msg.From.ID) != h.Core.Config.AdminID {
-		h.Core.SendMessage(msg.Chat.ID, "У вас нет доступа к этой команде.")
-		return
-	}
-
+func (h *ListHandler) list(ctx context.Context, msg *tgbotapi.Message, _ Args) {
 	questions, err := h.Core.Storage.GetAllQuestions()
 	if err != nil {
-		h.Core.SendMessage(msg.Chat.ID, "Ошибка при получении списка вопросов: "+err.Error())
+		h.Core.Reply(ctx, msg, "common.list_failed", i18n.Params{"error": err})
 		return
 	}
 	if len(questions) == 0 {
-		h.Core.SendMessage(msg.Chat.ID, "Вопросы отсутствуют.")
+		h.Core.Reply(ctx, msg, "list.empty")
 		return
 	}
 
+	// Срочные вопросы сверху, спам и оскорбления в конце; внутри группы — по ID
+	sort.SliceStable(questions, func(i, j int) bool {
+		return moderation.Priority(questions[i].Category) < moderation.Priority(questions[j].Category)
+	})
+
+	lang := h.Core.Lang(ctx, msg.From)
 	var result string
 	for _, q := range questions {
-		result += fmt.Sprintf("ID: %d | User: %s | Ответ: %s | Ответил: %t\n",
-			q.ID, q.Username, q.Answer, q.Answered)
+		answered := h.Core.T(lang, "list.no")
+		if q.Answered {
+			answered = h.Core.T(lang, "list.yes")
+		}
+		result += h.Core.T(lang, "list.row", i18n.Params{
+			"id":       q.ID,
+			"user":     q.Username,
+			"answer":   q.Answer,
+			"answered": answered,
+			"label":    classificationLabel(h.Core, lang, q),
+		})
+	}
+	// С переводами список быстро упирается в лимит Telegram на длину сообщения
+	for _, part := range core.SplitMessage(result, core.MaxMessageLength) {
+		h.Core.SendMessage(ctx, msg.Chat.ID, part)
+	}
+}
+
+// classificationLabel возвращает категорию, темы и перевод вопроса для вывода в списке.
+func classificationLabel(bc *core.BotCore, lang string, q *models.Question) string {
+	var label string
+	if q.Held {
+		label += " | " + bc.T(lang, "list.held")
+	}
+	if q.Rejected {
+		label += " | " + bc.T(lang, "list.rejected")
+	}
+	if q.Category != "" && q.Category != moderation.CategoryNormal {
+		label += " | " + strings.ToUpper(q.Category)
+	}
+	if len(q.Tags) > 0 {
+		label += " | #" + strings.Join(q.Tags, " #")
+	}
+	if q.Translation != "" {
+		label += bc.T(lang, "list.translation", i18n.Params{"lang": q.Language, "text": q.Text, "translation": q.Translation})
 	}
-	h.Core.SendMessage(msg.Chat.ID, result)
+	return label
 }
@@ -1,59 +1,48 @@
 package handlers
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
+	"context"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
 )
 
 type MediaHandler struct {
 	Core *core.BotCore
 }
 
-func (h *MediaHandler) CanHandle(cmd string) bool {
-	return cmd == "media"
+func (h *MediaHandler) Commands() []Command {
+	return []Command{{
+		Name: "media",
+		Role: core.RoleAdmin,
+		Args: []Arg{{Name: "id", Type: ArgInt}},
+		Run:  h.media,
+	}}
 }
 
-func (h *MediaHandler) Handle(msg *tgbotapi.Message) {
-	if int(msg.From.ID) != h.Core.Config.AdminID {
-		h.Core.SendMessage(msg.Chat.ID, "У вас нет доступа к этой команде.")
-		return
-	}
-
-	args := strings.SplitN(msg.Text, " ", 2)
-	if len(args) < 2 {
-		h.Core.SendMessage(msg.Chat.ID, "Использование: /media <id>")
-		return
-	}
-	qID, err := strconv.Atoi(args[1])
-	if err != nil {
-		h.Core.SendMessage(msg.Chat.ID, "Неверный ID вопроса.")
-		return
-	}
-
-	q, err := h.Core.Storage.GetQuestion(qID)
+func (h *MediaHandler) media(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	q, err := h.Core.Storage.GetQuestion(args.Int("id"))
 	if err != nil {
-		h.Core.SendMessage(msg.Chat.ID, "Вопрос не найден: "+err.Error())
+		h.Core.Reply(ctx, msg, "common.question_not_found", i18n.Params{"error": err})
 		return
 	}
 	if q.FileID == "" {
-		h.Core.SendMessage(msg.Chat.ID, fmt.Sprintf("У вопроса #%d нет медиафайла.", q.ID))
+		h.Core.Reply(ctx, msg, "media.none", i18n.Params{"id": q.ID})
 		return
 	}
 
+	caption := h.Core.T(h.Core.Lang(ctx, msg.From), "media.caption", i18n.Params{"id": q.ID, "text": q.Text})
 	switch q.MediaType {
 	case "photo":
 		photoMsg := tgbotapi.NewPhoto(msg.Chat.ID, tgbotapi.FileID(q.FileID))
-		photoMsg.Caption = fmt.Sprintf("Вопрос #%d: %s", q.ID, q.Text)
-		h.Core.BotAPI.Send(photoMsg)
+		photoMsg.Caption = caption
+		h.Core.Send(ctx, photoMsg)
 	case "video":
 		videoMsg := tgbotapi.NewVideo(msg.Chat.ID, tgbotapi.FileID(q.FileID))
-		videoMsg.Caption = fmt.Sprintf("Вопрос #%d: %s", q.ID, q.Text)
-		h.Core.BotAPI.Send(videoMsg)
+		videoMsg.Caption = caption
+		h.Core.Send(ctx, videoMsg)
 	default:
-		h.Core.SendMessage(msg.Chat.ID, "Неизвестный тип медиа: "+q.MediaType)
+		h.Core.Reply(ctx, msg, "media.unknown_type", i18n.Params{"type": q.MediaType})
 	}
 }
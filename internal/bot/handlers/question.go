@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/crisis"
+	"telegram-anonymous-bot/internal/filter"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/moderation"
+	"telegram-anonymous-bot/internal/translate"
+)
+
+// QuestionHandler принимает обычные (не командные) сообщения как анонимные вопросы.
+type QuestionHandler struct {
+	Core *core.BotCore
+}
+
+func (h *QuestionHandler) Handle(ctx context.Context, msg *tgbotapi.Message) {
+	q := &models.Question{
+		UserID:   int(msg.From.ID),
+		Username: msg.From.UserName,
+		Text:     msg.Text,
+		// Язык клиента Telegram — предварительная оценка, переводчик уточнит её по тексту
+		Language: translate.NormalizeLanguage(msg.From.LanguageCode),
+	}
+
+	switch {
+	case len(msg.Photo) > 0:
+		// Telegram присылает несколько размеров, последний — самый большой
+		q.FileID = msg.Photo[len(msg.Photo)-1].FileID
+		q.MediaType = "photo"
+		q.Text = msg.Caption
+	case msg.Video != nil:
+		q.FileID = msg.Video.FileID
+		q.MediaType = "video"
+		q.Text = msg.Caption
+	}
+
+	if q.Text == "" && q.FileID == "" {
+		h.Core.Reply(ctx, msg, "question.empty")
+		return
+	}
+
+	// Кризисные сообщения не должны отсеиваться фильтром: сразу отвечаем контактами
+	// помощи, помечаем вопрос срочным и поднимаем тревогу у всех администраторов.
+	var crisisMatch *crisis.Match
+	if h.Core.Crisis != nil {
+		if m, ok := h.Core.Crisis.Detect(q.Text, msg.From.LanguageCode); ok {
+			crisisMatch = &m
+			q.Category = moderation.CategoryUrgent
+			h.Core.SendMessage(ctx, msg.Chat.ID, m.Message)
+		}
+	}
+
+	var heldBy []string
+	if h.Core.Filter != nil && crisisMatch == nil {
+		d := h.Core.Filter.Check(q.Text)
+		switch d.Action {
+		case filter.ActionDrop:
+			slog.InfoContext(ctx, "Вопрос отброшен фильтром", "rules", d.Rules)
+			return
+		case filter.ActionReject:
+			if d.Message == "" {
+				h.Core.Reply(ctx, msg, "question.rejected")
+			} else {
+				h.Core.SendMessage(ctx, msg.Chat.ID, d.Message)
+			}
+			return
+		case filter.ActionHold:
+			q.Held = true
+			heldBy = d.Rules
+		}
+		q.Tags = d.Tags
+	}
+
+	if err := h.Core.Storage.SaveQuestion(q); err != nil {
+		h.Core.Reply(ctx, msg, "question.save_failed", i18n.Params{"error": err})
+		return
+	}
+	h.Core.Metrics.QuestionCreated()
+
+	h.Core.Reply(ctx, msg, "question.accepted", i18n.Params{"id": q.ID})
+	if crisisMatch != nil {
+		h.alertAdmins(ctx, q, crisisMatch.Keyword)
+	}
+	if h.Core.Translator != nil && q.Text != "" {
+		// Перевод обращается к нейросети, поэтому уведомление с переводом уходит из фона
+		h.Core.Go(func() {
+			h.translate(ctx, q)
+			h.notifyAdmin(ctx, q, heldBy)
+		})
+	} else {
+		h.notifyAdmin(ctx, q, heldBy)
+	}
+
+	if h.Core.Config.ClassifyQuestions && q.Text != "" {
+		h.Core.Go(func() { h.classify(ctx, q) })
+	}
+}
+
+// classify размечает вопрос нейросетью. Выполняется в фоне: при недоступной нейросети
+// вопрос просто остаётся без категории и попадает в /list как обычный.
+func (h *QuestionHandler) classify(ctx context.Context, q *models.Question) {
+	reply, err := h.Core.Generate(ctx, moderation.Prompt(q.Text))
+	if err != nil {
+		slog.ErrorContext(ctx, "Не удалось классифицировать вопрос", "question", q.ID, "error", err)
+		return
+	}
+	res, err := moderation.Parse(reply)
+	if err != nil {
+		slog.ErrorContext(ctx, "Не удалось разобрать классификацию", "question", q.ID, "error", err)
+		return
+	}
+
+	// Темы от локального фильтра сохраняем, добавляя к ним темы нейросети.
+	// Срочность, выставленную кризисным детектором, нейросеть не снимает.
+	tags := slices.Clone(q.Tags)
+	for _, tag := range res.Tags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	category := res.Category
+	if q.Category == moderation.CategoryUrgent {
+		category = moderation.CategoryUrgent
+	}
+	if err := h.Core.Storage.UpdateClassification(q.ID, category, tags); err != nil {
+		core.LogStorageError(ctx, "UpdateClassification", err)
+		return
+	}
+
+	if res.Category == moderation.CategoryUrgent && q.Category != moderation.CategoryUrgent {
+		h.Core.NotifyAdmins(ctx, "question.urgent", i18n.Params{"id": q.ID})
+	}
+}
+
+// translate переводит вопрос на язык администраторов и уточняет язык автора.
+// При ошибке вопрос остаётся без перевода.
+func (h *QuestionHandler) translate(ctx context.Context, q *models.Question) {
+	res, err := h.Core.Translate(ctx, q.Text, h.Core.Config.TranslateTo)
+	if err != nil {
+		slog.ErrorContext(ctx, "Не удалось перевести вопрос", "question", q.ID, "error", err)
+		return
+	}
+	if res.Language != "" {
+		q.Language = res.Language
+	}
+	q.Translation = res.Text
+	if err := h.Core.Storage.UpdateTranslation(q.ID, q.Language, q.Translation); err != nil {
+		core.LogStorageError(ctx, "UpdateTranslation", err)
+	}
+}
+
+// notifyAdmin сообщает администраторам о новом вопросе (каждому на его языке) и прикладывает
+// кнопки шаблонов. Для вопроса, задержанного фильтром, вместо шаблонов показывается кнопка «Пропустить».
+func (h *QuestionHandler) notifyAdmin(ctx context.Context, q *models.Question, heldBy []string) {
+	var templates []*models.Template
+	if !q.Held {
+		var err error
+		if templates, err = h.Core.Storage.GetAllTemplates(); err != nil {
+			core.LogStorageError(ctx, "GetAllTemplates", err)
+		}
+	}
+
+	for _, adminID := range h.Core.AdminIDs() {
+		lang := h.Core.UserLang(ctx, adminID, "")
+		notification := tgbotapi.NewMessage(adminID, questionNotificationText(h.Core, lang, q))
+		switch {
+		case q.Held:
+			notification.Text = h.Core.T(lang, "question.held", i18n.Params{
+				"rules": strings.Join(heldBy, ", "),
+				"text":  notification.Text,
+			})
+			notification.ReplyMarkup = releaseKeyboard(h.Core, lang, q.ID)
+		case len(templates) > 0:
+			notification.ReplyMarkup = templateKeyboard(q.ID, templates)
+		}
+		h.Core.Send(ctx, notification)
+	}
+}
+
+// alertAdmins отдельно и громко оповещает всех администраторов о кризисном сообщении.
+func (h *QuestionHandler) alertAdmins(ctx context.Context, q *models.Question, keyword string) {
+	h.Core.NotifyAdmins(ctx, "question.crisis_alert", i18n.Params{"id": q.ID, "keyword": keyword, "text": q.Text})
+}
+
+func questionNotificationText(bc *core.BotCore, lang string, q *models.Question) string {
+	text := bc.T(lang, "question.notification", i18n.Params{"id": q.ID, "user": q.Username, "text": q.Text})
+	if q.Translation != "" {
+		text += bc.T(lang, "question.translation", i18n.Params{"lang": q.Language, "translation": q.Translation})
+	}
+	if q.FileID != "" {
+		text += bc.T(lang, "question.attachment", i18n.Params{"type": q.MediaType, "id": q.ID})
+	}
+	if len(q.Tags) > 0 {
+		text += "\n#" + strings.Join(q.Tags, " #")
+	}
+	return text
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+)
+
+// ArgType — тип аргумента команды.
+type ArgType int
+
+const (
+	ArgWord ArgType = iota // одно слово
+	ArgInt                 // целое число
+	ArgText                // весь остаток строки; только последним
+)
+
+// Arg описывает аргумент команды. Название для справки берётся из ключа i18n "arg.<Name>".
+type Arg struct {
+	Name     string
+	Type     ArgType
+	Optional bool
+}
+
+// Command — объявление команды. Описание для /help берётся из ключа i18n "cmd.<Name>".
+type Command struct {
+	Name    string
+	Aliases []string
+	// Role — минимальная роль, которой доступна команда.
+	Role core.Role
+	Args []Arg
+	Run  func(ctx context.Context, msg *tgbotapi.Message, args Args)
+}
+
+// Args — разобранные аргументы команды по именам.
+type Args map[string]any
+
+// Has сообщает, передан ли необязательный аргумент.
+func (a Args) Has(name string) bool {
+	_, ok := a[name]
+	return ok
+}
+
+// Int возвращает значение аргумента ArgInt (0, если не передан).
+func (a Args) Int(name string) int {
+	n, _ := a[name].(int)
+	return n
+}
+
+// String возвращает значение аргумента ArgWord или ArgText ("" если не передан).
+func (a Args) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+// errUsage — аргументов не хватает или они лишние.
+var errUsage = errors.New("неверные аргументы")
+
+// NumberError — аргумент ArgInt не является числом.
+type NumberError struct {
+	Arg   string
+	Value string
+}
+
+func (e *NumberError) Error() string {
+	return fmt.Sprintf("%s: %q — не число", e.Arg, e.Value)
+}
+
+// ParseArgs разбирает строку аргументов команды по описанию spec.
+func ParseArgs(spec []Arg, input string) (Args, error) {
+	args := make(Args, len(spec))
+	rest := strings.TrimSpace(input)
+	for _, a := range spec {
+		if rest == "" {
+			if !a.Optional {
+				return nil, errUsage
+			}
+			break
+		}
+
+		value := rest
+		if a.Type == ArgText {
+			rest = ""
+		} else if i := strings.IndexFunc(rest, unicode.IsSpace); i >= 0 {
+			value, rest = rest[:i], strings.TrimLeftFunc(rest[i:], unicode.IsSpace)
+		} else {
+			rest = ""
+		}
+
+		if a.Type == ArgInt {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, &NumberError{Arg: a.Name, Value: value}
+			}
+			args[a.Name] = n
+			continue
+		}
+		args[a.Name] = value
+	}
+	if rest != "" {
+		return nil, errUsage
+	}
+	return args, nil
+}
+
+// Usage возвращает строку вызова команды на языке lang, например "/answer <id> <ответ>".
+func Usage(bc *core.BotCore, lang string, cmd Command) string {
+	parts := []string{"/" + cmd.Name}
+	for _, a := range cmd.Args {
+		name := bc.T(lang, "arg."+a.Name)
+		if a.Optional {
+			parts = append(parts, "["+name+"]")
+		} else {
+			parts = append(parts, "<"+name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Registry — реестр команд: поиск по имени и псевдонимам, разбор аргументов и список для /help.
+type Registry struct {
+	core     *core.BotCore
+	commands []Command
+	routes   map[string]HandlerFunc
+}
+
+func NewRegistry(bc *core.BotCore) *Registry {
+	return &Registry{core: bc, routes: make(map[string]HandlerFunc)}
+}
+
+// Register добавляет команду, обернув её обработку цепочкой middleware.
+// Аргументы разбираются после middleware; при ошибке пользователь получает подсказку.
+func (r *Registry) Register(cmd Command, mws ...Middleware) {
+	handle := Wrap(func(ctx context.Context, msg *tgbotapi.Message) {
+		args, err := ParseArgs(cmd.Args, msg.CommandArguments())
+		if err != nil {
+			lang := r.core.Lang(ctx, msg.From)
+			usage := Usage(r.core, lang, cmd)
+			var numErr *NumberError
+			if errors.As(err, &numErr) {
+				r.core.Reply(ctx, msg, "common.bad_number", i18n.Params{"value": numErr.Value, "usage": usage})
+			} else {
+				r.core.Reply(ctx, msg, "common.usage", i18n.Params{"usage": usage})
+			}
+			return
+		}
+		cmd.Run(ctx, msg, args)
+	}, mws...)
+
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if _, ok := r.routes[name]; ok {
+			panic(fmt.Sprintf("команда /%s зарегистрирована дважды", name))
+		}
+		r.routes[name] = handle
+	}
+	r.commands = append(r.commands, cmd)
+}
+
+// Lookup находит обработку команды по имени или псевдониму.
+func (r *Registry) Lookup(name string) (HandlerFunc, bool) {
+	handle, ok := r.routes[name]
+	return handle, ok
+}
+
+// Commands возвращает команды, доступные роли role, в порядке регистрации.
+func (r *Registry) Commands(role core.Role) []Command {
+	var commands []Command
+	for _, cmd := range r.commands {
+		if cmd.Role <= role {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands
+}
+
+// Menu возвращает меню команд для Telegram (setMyCommands) на языке lang:
+// команды, доступные роли role, без псевдонимов.
+func (r *Registry) Menu(lang string, role core.Role) []tgbotapi.BotCommand {
+	var menu []tgbotapi.BotCommand
+	for _, cmd := range r.Commands(role) {
+		menu = append(menu, tgbotapi.BotCommand{Command: cmd.Name, Description: r.core.T(lang, "cmd."+cmd.Name)})
+	}
+	return menu
+}
+
+// AdminMenu возвращает запрос, показывающий администратору adminID его меню на языке lang.
+func (r *Registry) AdminMenu(adminID int64, lang string) tgbotapi.SetMyCommandsConfig {
+	return tgbotapi.NewSetMyCommandsWithScope(tgbotapi.NewBotCommandScopeChat(adminID), r.Menu(lang, core.RoleAdmin)...)
+}
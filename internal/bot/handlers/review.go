@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+)
+
+// ReviewHandler обрабатывает команды /reject <id> [причина] и /tag <id> [темы].
+type ReviewHandler struct {
+	Core *core.BotCore
+}
+
+func (h *ReviewHandler) Commands() []Command {
+	return []Command{
+		{
+			Name: "reject",
+			Role: core.RoleAdmin,
+			Args: []Arg{{Name: "id", Type: ArgInt}, {Name: "reason", Type: ArgText, Optional: true}},
+			Run:  h.reject,
+		},
+		{
+			Name: "tag",
+			Role: core.RoleAdmin,
+			Args: []Arg{{Name: "id", Type: ArgInt}, {Name: "tags", Type: ArgText, Optional: true}},
+			Run:  h.tag,
+		},
+	}
+}
+
+func (h *ReviewHandler) reject(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	q, err := h.Core.Storage.GetQuestion(args.Int("id"))
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.question_not_found", i18n.Params{"error": err})
+		return
+	}
+
+	err = h.Core.RejectQuestion(ctx, q, args.String("reason"))
+	switch {
+	case errors.Is(err, core.ErrAlreadyAnswered), errors.Is(err, core.ErrRejected):
+		h.Core.Reply(ctx, msg, closedKey(err))
+	case err != nil:
+		h.Core.Reply(ctx, msg, "common.update_failed", i18n.Params{"error": err})
+	default:
+		h.Core.Reply(ctx, msg, "reject.done", i18n.Params{"id": q.ID})
+	}
+}
+
+// tag заменяет темы вопроса; без тем — очищает их.
+func (h *ReviewHandler) tag(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	q, err := h.Core.Storage.GetQuestion(args.Int("id"))
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.question_not_found", i18n.Params{"error": err})
+		return
+	}
+
+	tags := core.ParseTags(args.String("tags"))
+	if err := h.Core.TagQuestion(ctx, q, tags); err != nil {
+		h.Core.Reply(ctx, msg, "common.update_failed", i18n.Params{"error": err})
+		return
+	}
+	if len(tags) == 0 {
+		h.Core.Reply(ctx, msg, "tag.cleared", i18n.Params{"id": q.ID})
+		return
+	}
+	h.Core.Reply(ctx, msg, "tag.done", i18n.Params{"id": q.ID, "tags": strings.Join(tags, " #")})
+}
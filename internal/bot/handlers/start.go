@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"telegram-anonymous-bot/internal/bot/core"
 )
@@ -9,10 +11,10 @@ type StartHandler struct {
 	Core *core.BotCore
 }
 
-func (h *StartHandler) CanHandle(cmd string) bool {
-	return cmd == "start"
+func (h *StartHandler) Commands() []Command {
+	return []Command{{Name: "start", Run: h.start}}
 }
 
-func (h *StartHandler) Handle(msg *tgbotapi.Message) {
-	h.Core.SendMessage(msg.Chat.ID, "Привет! Я анонимный бот. Введите /help для списка команд.")
+func (h *StartHandler) start(ctx context.Context, msg *tgbotapi.Message, _ Args) {
+	h.Core.Reply(ctx, msg, "start.greeting")
 }
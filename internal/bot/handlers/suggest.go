@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/translate"
+)
+
+// suggestCallbackPrefix — префикс данных кнопок черновика: "sug:<действие>:<id черновика>"
+const suggestCallbackPrefix = "sug:"
+
+// SuggestHandler обрабатывает команду /suggest <id> — черновик ответа от нейросети.
+// Черновик показывается только администратору; пользователю он уходит лишь по кнопке.
+type SuggestHandler struct {
+	Core *core.BotCore
+}
+
+func (h *SuggestHandler) Commands() []Command {
+	return []Command{{
+		Name: "suggest",
+		Role: core.RoleAdmin,
+		Args: []Arg{{Name: "id", Type: ArgInt}},
+		Run:  h.suggest,
+	}}
+}
+
+func (h *SuggestHandler) suggest(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	q, err := h.Core.Storage.GetQuestion(args.Int("id"))
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.question_not_found", i18n.Params{"error": err})
+		return
+	}
+	if err := core.CheckOpen(q); err != nil {
+		h.Core.Reply(ctx, msg, closedKey(err))
+		return
+	}
+	if q.Text == "" {
+		h.Core.Reply(ctx, msg, "suggest.no_text", i18n.Params{"id": q.ID})
+		return
+	}
+
+	examples, err := h.recentAnswers(h.Core.Config.SuggestExamples)
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.list_failed", i18n.Params{"error": err})
+		return
+	}
+
+	lang := h.Core.Lang(ctx, msg.From)
+	text, err := h.Core.Generate(ctx, BuildSuggestPrompt(q, examples, lang))
+	if err != nil {
+		h.Core.Reply(ctx, msg, "common.llm_error", i18n.Params{"error": err})
+		return
+	}
+
+	draft := &models.Draft{QuestionID: q.ID, Text: strings.TrimSpace(text)}
+	if err := h.Core.Storage.SaveDraft(draft); err != nil {
+		h.Core.Reply(ctx, msg, "suggest.save_failed", i18n.Params{"error": err})
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, h.Core.T(lang, "suggest.draft", i18n.Params{
+		"id":       q.ID,
+		"question": q.Text,
+		"draft":    draft.Text,
+	}))
+	reply.ReplyMarkup = draftKeyboard(h.Core, lang, draft.ID)
+	h.Core.Send(ctx, reply)
+}
+
+// recentAnswers возвращает до n последних отвеченных вопросов — примеры стиля для нейросети.
+func (h *SuggestHandler) recentAnswers(n int) ([]*models.Question, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	questions, err := h.Core.Storage.GetAllQuestions()
+	if err != nil {
+		return nil, err
+	}
+
+	var answered []*models.Question
+	for i := len(questions) - 1; i >= 0 && len(answered) < n; i-- {
+		if q := questions[i]; q.Answered && q.Text != "" && q.Answer != "" {
+			answered = append(answered, q)
+		}
+	}
+	return answered, nil
+}
+
+// BuildSuggestPrompt формирует запрос к нейросети: примеры прошлых ответов и новый вопрос.
+// Черновик пишется на языке lang — его читает и правит администратор.
+func BuildSuggestPrompt(q *models.Question, examples []*models.Question, lang string) string {
+	var b strings.Builder
+	b.WriteString("Ты помогаешь администратору анонимного бота отвечать на вопросы пользователей. ")
+	fmt.Fprintf(&b, "Напиши вежливый и краткий ответ на языке: %s (код %s). Верни только текст ответа.\n\n",
+		translate.LanguageName(lang), lang)
+
+	if len(examples) > 0 {
+		b.WriteString("Примеры прошлых ответов администратора (соблюдай их стиль):\n")
+		for _, e := range examples {
+			fmt.Fprintf(&b, "Вопрос: %s\nОтвет: %s\n\n", e.Text, e.Answer)
+		}
+	}
+
+	fmt.Fprintf(&b, "Вопрос: %s\nОтвет:", q.Text)
+	return b.String()
+}
+
+func draftKeyboard(bc *core.BotCore, lang string, draftID int) tgbotapi.InlineKeyboardMarkup {
+	data := func(action string) string {
+		return fmt.Sprintf("%s%s:%d", suggestCallbackPrefix, action, draftID)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(bc.T(lang, "suggest.button_send"), data("send")),
+		tgbotapi.NewInlineKeyboardButtonData(bc.T(lang, "suggest.button_edit"), data("edit")),
+		tgbotapi.NewInlineKeyboardButtonData(bc.T(lang, "suggest.button_drop"), data("drop")),
+	))
+}
+
+// SuggestCallbackHandler обрабатывает кнопки под черновиком: отправить, изменить, удалить.
+type SuggestCallbackHandler struct {
+	Core *core.BotCore
+}
+
+func (h *SuggestCallbackHandler) CanHandleCallback(data string) bool {
+	return strings.HasPrefix(data, suggestCallbackPrefix)
+}
+
+func (h *SuggestCallbackHandler) HandleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if !h.Core.IsAdmin(cb.From.ID) {
+		h.Core.AnswerCallback(ctx, cb, "common.no_access")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(cb.Data, suggestCallbackPrefix), ":")
+	if len(parts) != 2 {
+		h.Core.AnswerCallback(ctx, cb, "common.bad_callback")
+		return
+	}
+	draftID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		h.Core.AnswerCallback(ctx, cb, "common.bad_callback")
+		return
+	}
+
+	draft, err := h.Core.Storage.GetDraft(draftID)
+	if err != nil {
+		h.Core.AnswerCallback(ctx, cb, "suggest.not_found")
+		return
+	}
+
+	switch parts[0] {
+	case "send":
+		h.send(ctx, cb, draft)
+	case "edit":
+		// Черновик остаётся в базе: администратор правит текст и отвечает через /answer
+		h.Core.Request(ctx, tgbotapi.NewCallback(cb.ID, ""))
+		h.Core.SendMessage(ctx, cb.From.ID, h.Core.T(h.Core.Lang(ctx, cb.From), "suggest.edit_hint"))
+		h.Core.SendMessage(ctx, cb.From.ID, fmt.Sprintf("/answer %d %s", draft.QuestionID, draft.Text))
+	case "drop":
+		if err := h.Core.Storage.DeleteDraft(draft.ID); err != nil {
+			h.Core.AnswerCallback(ctx, cb, "suggest.drop_failed", i18n.Params{"error": err})
+			return
+		}
+		h.Core.AnswerCallback(ctx, cb, "suggest.dropped")
+		h.closeDraftMessage(ctx, cb, "suggest.dropped_status")
+	default:
+		h.Core.AnswerCallback(ctx, cb, "common.bad_callback")
+	}
+}
+
+func (h *SuggestCallbackHandler) send(ctx context.Context, cb *tgbotapi.CallbackQuery, draft *models.Draft) {
+	q, err := h.Core.Storage.GetQuestion(draft.QuestionID)
+	if err != nil {
+		h.Core.AnswerCallback(ctx, cb, "common.question_not_found", i18n.Params{"error": err})
+		return
+	}
+	if err := core.CheckOpen(q); err != nil {
+		h.Core.AnswerCallback(ctx, cb, closedKey(err))
+		return
+	}
+
+	err = h.Core.AnswerQuestion(ctx, q, draft.Text)
+	switch {
+	case errors.Is(err, core.ErrAlreadyAnswered), errors.Is(err, core.ErrRejected):
+		h.Core.AnswerCallback(ctx, cb, closedKey(err))
+		return
+	case err != nil:
+		h.Core.AnswerCallback(ctx, cb, "common.update_failed", i18n.Params{"error": err})
+		return
+	}
+	if err := h.Core.Storage.DeleteDraft(draft.ID); err != nil {
+		h.Core.SendMessage(ctx, cb.From.ID, h.Core.T(h.Core.Lang(ctx, cb.From), "suggest.drop_failed", i18n.Params{"error": err}))
+	}
+
+	h.Core.AnswerCallback(ctx, cb, "common.answer_sent", i18n.Params{"id": q.ID})
+	h.closeDraftMessage(ctx, cb, "suggest.sent_status")
+}
+
+// closeDraftMessage убирает кнопки под черновиком и дописывает итог (ключ текста statusKey).
+func (h *SuggestCallbackHandler) closeDraftMessage(ctx context.Context, cb *tgbotapi.CallbackQuery, statusKey string) {
+	if cb.Message == nil {
+		return
+	}
+	h.Core.Request(ctx, tgbotapi.NewEditMessageText(cb.Message.Chat.ID, cb.Message.MessageID,
+		cb.Message.Text+"\n\n"+h.Core.T(h.Core.Lang(ctx, cb.From), statusKey)))
+}
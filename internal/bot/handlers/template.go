@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/storage"
+)
+
+const (
+	// templateCallbackPrefix — префикс данных inline-кнопки: "tpl:<id вопроса>:<id шаблона>"
+	templateCallbackPrefix = "tpl:"
+	// maxTemplateButtons ограничивает число кнопок под уведомлением о вопросе
+	maxTemplateButtons = 10
+)
+
+// TemplateData — значения, доступные в шаблоне ответа.
+//
+//	{{.ID}}       — ID вопроса
+//	{{.Question}} — текст вопроса
+//	{{.Date}}     — текущая дата (ДД.ММ.ГГГГ)
+//	{{.AskedAt}}  — дата, когда был задан вопрос
+type TemplateData struct {
+	ID       int
+	Question string
+	Date     string
+	AskedAt  string
+}
+
+// RenderTemplate подставляет данные вопроса в шаблон ответа. Ошибка разбора или подстановки
+// возвращается как есть: имя шаблона в ответ администратору добавляет вызывающий.
+func RenderTemplate(t *models.Template, q *models.Question, now time.Time) (string, error) {
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(t.Text)
+	if err != nil {
+		return "", err
+	}
+
+	data := TemplateData{
+		ID:       q.ID,
+		Question: q.Text,
+		Date:     now.Format("02.01.2006"),
+	}
+	if !q.CreatedAt.IsZero() {
+		data.AskedAt = q.CreatedAt.Format("02.01.2006")
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// templateKeyboard строит inline-клавиатуру выбора шаблона для вопроса.
+func templateKeyboard(questionID int, templates []*models.Template) tgbotapi.InlineKeyboardMarkup {
+	if len(templates) > maxTemplateButtons {
+		templates = templates[:maxTemplateButtons]
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, t := range templates {
+		data := fmt.Sprintf("%s%d:%d", templateCallbackPrefix, questionID, t.ID)
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("#"+t.Name, data))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// TemplateHandler обрабатывает команду /template (add|list|delete)
+type TemplateHandler struct {
+	Core *core.BotCore
+}
+
+func (h *TemplateHandler) Commands() []Command {
+	return []Command{{
+		Name: "template",
+		Role: core.RoleAdmin,
+		Args: []Arg{{Name: "action"}, {Name: "name", Optional: true}, {Name: "text", Type: ArgText, Optional: true}},
+		Run:  h.template,
+	}}
+}
+
+func (h *TemplateHandler) template(ctx context.Context, msg *tgbotapi.Message, args Args) {
+	switch args.String("action") {
+	case "add":
+		if !args.Has("text") {
+			h.Core.Reply(ctx, msg, "template.usage")
+			return
+		}
+		t := &models.Template{Name: strings.TrimPrefix(args.String("name"), "#"), Text: args.String("text")}
+		// Проверяем шаблон до сохранения, чтобы ошибка не всплыла при ответе
+		if _, err := RenderTemplate(t, &models.Question{}, time.Now()); err != nil {
+			h.Core.Reply(ctx, msg, "template.invalid", i18n.Params{"name": t.Name, "error": err})
+			return
+		}
+		if err := h.Core.Storage.SaveTemplate(t); err != nil {
+			h.Core.Reply(ctx, msg, "template.save_failed", i18n.Params{"error": err})
+			return
+		}
+		h.Core.Reply(ctx, msg, "template.saved", i18n.Params{"name": t.Name})
+	case "list":
+		templates, err := h.Core.Storage.GetAllTemplates()
+		if err != nil {
+			h.Core.Reply(ctx, msg, "template.list_failed", i18n.Params{"error": err})
+			return
+		}
+		if len(templates) == 0 {
+			h.Core.Reply(ctx, msg, "template.empty")
+			return
+		}
+		var result string
+		for _, t := range templates {
+			result += fmt.Sprintf("#%s: %s\n", t.Name, t.Text)
+		}
+		h.Core.SendMessage(ctx, msg.Chat.ID, result)
+	case "delete":
+		if !args.Has("name") || args.Has("text") {
+			h.Core.Reply(ctx, msg, "template.usage")
+			return
+		}
+		name := strings.TrimPrefix(args.String("name"), "#")
+		err := h.Core.Storage.DeleteTemplate(name)
+		if errors.Is(err, storage.ErrTemplateNotFound) {
+			h.Core.Reply(ctx, msg, "template.unknown", i18n.Params{"name": name})
+			return
+		}
+		if err != nil {
+			h.Core.Reply(ctx, msg, "template.delete_failed", i18n.Params{"error": err})
+			return
+		}
+		h.Core.Reply(ctx, msg, "template.deleted", i18n.Params{"name": name})
+	default:
+		h.Core.Reply(ctx, msg, "template.usage")
+	}
+}
+
+// TemplateCallbackHandler отвечает на вопрос шаблоном, выбранным кнопкой под уведомлением.
+type TemplateCallbackHandler struct {
+	Core *core.BotCore
+}
+
+func (h *TemplateCallbackHandler) CanHandleCallback(data string) bool {
+	return strings.HasPrefix(data, templateCallbackPrefix)
+}
+
+func (h *TemplateCallbackHandler) HandleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	if !h.Core.IsAdmin(cb.From.ID) {
+		h.Core.AnswerCallback(ctx, cb, "common.no_access")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(cb.Data, templateCallbackPrefix), ":")
+	if len(parts) != 2 {
+		h.Core.AnswerCallback(ctx, cb, "common.bad_callback")
+		return
+	}
+	qID, err1 := strconv.Atoi(parts[0])
+	tID, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		h.Core.AnswerCallback(ctx, cb, "common.bad_callback")
+		return
+	}
+
+	q, err := h.Core.Storage.GetQuestion(qID)
+	if err != nil {
+		h.Core.AnswerCallback(ctx, cb, "common.question_not_found", i18n.Params{"error": err})
+		return
+	}
+	if err := core.CheckOpen(q); err != nil {
+		h.Core.AnswerCallback(ctx, cb, closedKey(err))
+		return
+	}
+
+	t, err := h.Core.Storage.GetTemplate(tID)
+	if err != nil {
+		h.Core.AnswerCallback(ctx, cb, "template.not_found", i18n.Params{"error": err})
+		return
+	}
+	answerText, err := RenderTemplate(t, q, time.Now())
+	if err != nil {
+		h.Core.AnswerCallback(ctx, cb, "template.invalid", i18n.Params{"name": t.Name, "error": err})
+		return
+	}
+
+	err = h.Core.AnswerQuestion(ctx, q, answerText)
+	switch {
+	case errors.Is(err, core.ErrAlreadyAnswered), errors.Is(err, core.ErrRejected):
+		h.Core.AnswerCallback(ctx, cb, closedKey(err))
+		return
+	case err != nil:
+		h.Core.AnswerCallback(ctx, cb, "common.update_failed", i18n.Params{"error": err})
+		return
+	}
+
+	h.Core.AnswerCallback(ctx, cb, "common.answer_sent", i18n.Params{"id": qID})
+	if cb.Message != nil {
+		// Убираем кнопки и дописываем отправленный ответ к уведомлению
+		edit := tgbotapi.NewEditMessageText(cb.Message.Chat.ID, cb.Message.MessageID,
+			h.Core.T(h.Core.Lang(ctx, cb.From), "template.answered", i18n.Params{
+				"text":   cb.Message.Text,
+				"name":   t.Name,
+				"answer": answerText,
+			}))
+		h.Core.Request(ctx, edit)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/i18n"
+)
+
+// LoginLinks выдаёт одноразовые ссылки входа в веб-панель (см. internal/web).
+type LoginLinks interface {
+	LoginLink(userID int64) (string, error)
+	LoginTTL() time.Duration
+}
+
+// WebHandler обрабатывает команду /web: присылает администратору ссылку входа в веб-панель.
+type WebHandler struct {
+	Core  *core.BotCore
+	Links LoginLinks
+}
+
+func (h *WebHandler) Commands() []Command {
+	return []Command{{Name: "web", Role: core.RoleAdmin, Run: h.link}}
+}
+
+// link отправляет ссылку в личный чат с администратором, даже если команда пришла из группы.
+// Предпросмотр отключён, чтобы Telegram не открывал ссылку сам.
+func (h *WebHandler) link(ctx context.Context, msg *tgbotapi.Message, _ Args) {
+	url, err := h.Links.LoginLink(msg.From.ID)
+	if err != nil {
+		h.Core.Reply(ctx, msg, "web.link_failed", i18n.Params{"error": err})
+		return
+	}
+	minutes := max(int(h.Links.LoginTTL().Minutes()), 1)
+	reply := tgbotapi.NewMessage(msg.From.ID, h.Core.N(h.Core.Lang(ctx, msg.From), "web.link", minutes, i18n.Params{"url": url}))
+	reply.DisableWebPagePreview = true
+	h.Core.Send(ctx, reply)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/bot/handlers"
+	"telegram-anonymous-bot/internal/metrics"
+)
+
+// CallbackName возвращает имя обработки нажатия для логов и метрик: "callback:" и префикс
+// данных кнопки ("callback:sug"), без аргументов — чтобы не плодить метки.
+func CallbackName(cb *tgbotapi.CallbackQuery) string {
+	prefix, _, _ := strings.Cut(cb.Data, ":")
+	return "callback:" + prefix
+}
+
+// RecoverCallback — Recover для нажатий на кнопки: паника пишется в лог со стеком,
+// а администратор видит уведомление о внутренней ошибке.
+func RecoverCallback(bc *core.BotCore) handlers.CallbackMiddleware {
+	return func(next handlers.CallbackFunc) handlers.CallbackFunc {
+		return func(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.ErrorContext(ctx, "Паника в обработке", "handler", CallbackName(cb), "panic", r, "stack", string(debug.Stack()))
+					bc.AnswerCallback(ctx, cb, "common.internal_error")
+				}
+			}()
+			next(ctx, cb)
+		}
+	}
+}
+
+// LoggingCallback — Logging для нажатий на кнопки.
+func LoggingCallback() handlers.CallbackMiddleware {
+	return func(next handlers.CallbackFunc) handlers.CallbackFunc {
+		return func(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+			start := time.Now()
+			next(ctx, cb)
+			slog.InfoContext(ctx, "Обработано", "handler", CallbackName(cb), "duration", time.Since(start).Round(time.Millisecond))
+		}
+	}
+}
+
+// MeasureCallback — Measure для нажатий на кнопки: метрики команд с именем CallbackName.
+// Панику передаёт дальше — ставьте MeasureCallback внутри RecoverCallback.
+func MeasureCallback(set *metrics.Set) handlers.CallbackMiddleware {
+	return func(next handlers.CallbackFunc) handlers.CallbackFunc {
+		return func(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+			start := time.Now()
+			ctx, outcome := metrics.WithOutcome(ctx)
+			panicked := true
+			defer func() {
+				if panicked {
+					*outcome = metrics.OutcomePanic
+				}
+				set.Command(CallbackName(cb), *outcome, time.Since(start))
+			}()
+			next(ctx, cb)
+			panicked = false
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/handlers"
+	"telegram-anonymous-bot/internal/metrics"
+)
+
+// Measure учитывает в set (nil — не нужно) вызовы и длительность команд и итог обработки,
+// который отмечают middleware дальше по цепочке и ответы с ошибкой; паника даёт итог
+// metrics.OutcomePanic. Панику передаёт дальше — ставьте Measure внутри Recover.
+func Measure(set *metrics.Set) handlers.Middleware {
+	return func(next handlers.HandlerFunc) handlers.HandlerFunc {
+		return func(ctx context.Context, msg *tgbotapi.Message) {
+			start := time.Now()
+			ctx, outcome := metrics.WithOutcome(ctx)
+			panicked := true
+			defer func() {
+				if panicked {
+					*outcome = metrics.OutcomePanic
+				}
+				set.Command(Name(msg), *outcome, time.Since(start))
+			}()
+			next(ctx, msg)
+			panicked = false
+		}
+	}
+}
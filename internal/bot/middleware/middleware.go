@@ -0,0 +1,110 @@
+// Package middleware — общая логика вокруг обработки команд, вопросов и нажатий на кнопки: восстановление
+// после паники, логирование, метрики, проверка роли, бана и частоты сообщений.
+// Цепочка собирается для каждой команды при регистрации в реестре TelegramBot.
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/bot/handlers"
+	"telegram-anonymous-bot/internal/metrics"
+)
+
+// Name возвращает имя обработки для логов и метрик: команду или "message" для вопросов.
+func Name(msg *tgbotapi.Message) string {
+	if cmd := msg.Command(); cmd != "" {
+		return cmd
+	}
+	return "message"
+}
+
+// Recover перехватывает панику в хендлере: пишет её в лог со стеком и сообщает
+// пользователю о внутренней ошибке, не роняя обработчик обновлений.
+func Recover(bc *core.BotCore) handlers.Middleware {
+	return func(next handlers.HandlerFunc) handlers.HandlerFunc {
+		return func(ctx context.Context, msg *tgbotapi.Message) {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.ErrorContext(ctx, "Паника в обработке", "handler", Name(msg), "panic", r, "stack", string(debug.Stack()))
+					bc.Reply(ctx, msg, "common.internal_error")
+				}
+			}()
+			next(ctx, msg)
+		}
+	}
+}
+
+// Logging пишет в лог каждую обработку с длительностью; команда, пользователь и чат
+// приходят из атрибутов context.
+func Logging() handlers.Middleware {
+	return func(next handlers.HandlerFunc) handlers.HandlerFunc {
+		return func(ctx context.Context, msg *tgbotapi.Message) {
+			start := time.Now()
+			next(ctx, msg)
+			slog.InfoContext(ctx, "Обработано", "handler", Name(msg), "duration", time.Since(start).Round(time.Millisecond))
+		}
+	}
+}
+
+// RequireRole пропускает только пользователей с ролью не ниже role.
+func RequireRole(bc *core.BotCore, role core.Role) handlers.Middleware {
+	return func(next handlers.HandlerFunc) handlers.HandlerFunc {
+		return func(ctx context.Context, msg *tgbotapi.Message) {
+			if bc.Role(msg.From.ID) < role {
+				metrics.SetOutcome(ctx, metrics.OutcomeDenied)
+				bc.Reply(ctx, msg, "common.no_access")
+				return
+			}
+			next(ctx, msg)
+		}
+	}
+}
+
+// CheckBan отклоняет сообщения забаненных пользователей. Администраторов не проверяет;
+// при ошибке базы сообщение пропускается, чтобы сбой не блокировал всех.
+func CheckBan(bc *core.BotCore) handlers.Middleware {
+	return func(next handlers.HandlerFunc) handlers.HandlerFunc {
+		return func(ctx context.Context, msg *tgbotapi.Message) {
+			if !bc.IsAdmin(msg.From.ID) {
+				banned, err := bc.Storage.IsBanned(int(msg.From.ID))
+				if err != nil {
+					core.LogStorageError(ctx, "IsBanned", err)
+				}
+				if banned {
+					metrics.SetOutcome(ctx, metrics.OutcomeBanned)
+					bc.Reply(ctx, msg, "common.banned")
+					return
+				}
+			}
+			next(ctx, msg)
+		}
+	}
+}
+
+// RateLimit ограничивает частоту сообщений пользователя; администраторов не ограничивает.
+// О превышении пользователь узнаёт один раз, пока лимит не восстановится. nil — без ограничения.
+func RateLimit(bc *core.BotCore, limiter *Limiter) handlers.Middleware {
+	return func(next handlers.HandlerFunc) handlers.HandlerFunc {
+		if limiter == nil {
+			return next
+		}
+		return func(ctx context.Context, msg *tgbotapi.Message) {
+			if !bc.IsAdmin(msg.From.ID) {
+				allowed, notify := limiter.Allow(msg.From.ID, time.Now())
+				if !allowed {
+					metrics.SetOutcome(ctx, metrics.OutcomeRateLimited)
+					if notify {
+						bc.Reply(ctx, msg, "common.rate_limited")
+					}
+					return
+				}
+			}
+			next(ctx, msg)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/handlers"
+	"telegram-anonymous-bot/internal/bot/middleware"
+	"telegram-anonymous-bot/internal/metrics"
+)
+
+func TestWrapOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) handlers.Middleware {
+		return func(next handlers.HandlerFunc) handlers.HandlerFunc {
+			return func(ctx context.Context, msg *tgbotapi.Message) {
+				calls = append(calls, name)
+				next(ctx, msg)
+			}
+		}
+	}
+
+	handle := handlers.Wrap(func(context.Context, *tgbotapi.Message) { calls = append(calls, "handler") }, trace("a"), trace("b"))
+	handle(context.Background(), &tgbotapi.Message{})
+
+	if got := strings.Join(calls, ","); got != "a,b,handler" {
+		t.Errorf("Expected middleware to run in order, got %s", got)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := middleware.NewLimiter(6, 2) // одно сообщение в 10 секунд, два подряд
+	now := time.Now()
+
+	want := []struct{ allowed, notify bool }{{true, false}, {true, false}, {false, true}, {false, false}}
+	for i, w := range want {
+		if allowed, notify := limiter.Allow(1, now); allowed != w.allowed || notify != w.notify {
+			t.Errorf("Call %d: expected (%v, %v), got (%v, %v)", i, w.allowed, w.notify, allowed, notify)
+		}
+	}
+	if allowed, _ := limiter.Allow(2, now); !allowed {
+		t.Errorf("Expected limits to be per user")
+	}
+	if allowed, _ := limiter.Allow(1, now.Add(10*time.Second)); !allowed {
+		t.Errorf("Expected the limit to recover over time")
+	}
+	if allowed, notify := limiter.Allow(1, now.Add(10*time.Second)); allowed || !notify {
+		t.Errorf("Expected a new warning after an allowed message, got (%v, %v)", allowed, notify)
+	}
+
+	if middleware.NewLimiter(0, 5) != nil {
+		t.Errorf("Expected zero rate to disable the limiter")
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	set := metrics.New()
+	msg := &tgbotapi.Message{Text: "/list", Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 5}}}
+
+	handlers.Wrap(func(context.Context, *tgbotapi.Message) {}, middleware.Measure(set))(context.Background(), msg)
+	handlers.Wrap(func(ctx context.Context, _ *tgbotapi.Message) {
+		metrics.SetOutcome(ctx, metrics.OutcomeDenied)
+	}, middleware.Measure(set))(context.Background(), msg)
+	func() {
+		defer func() { recover() }()
+		handlers.Wrap(func(context.Context, *tgbotapi.Message) { panic("boom") }, middleware.Measure(set))(context.Background(), msg)
+	}()
+
+	var out strings.Builder
+	set.Registry().WriteTo(&out)
+	for _, outcome := range []string{metrics.OutcomeOK, metrics.OutcomeDenied, metrics.OutcomePanic} {
+		want := `tgbot_commands_total{command="list",outcome="` + outcome + `"} 1`
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %s in metrics, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// maxBuckets — после скольких пользователей чистить полностью восстановившиеся лимиты.
+const maxBuckets = 10000
+
+// Limiter — ограничение частоты по алгоритму token bucket для каждого пользователя:
+// burst сообщений подряд, затем perMinute в минуту.
+type Limiter struct {
+	mu        sync.Mutex
+	perMinute float64
+	burst     float64
+	buckets   map[int64]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// limited — пользователь уже получил сообщение о превышении лимита.
+	limited bool
+}
+
+// NewLimiter создаёт ограничение; perMinute <= 0 — без ограничения (nil).
+func NewLimiter(perMinute, burst int) *Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		perMinute: float64(perMinute),
+		burst:     float64(burst),
+		buckets:   make(map[int64]*bucket),
+	}
+}
+
+// Allow расходует одно сообщение пользователя key. notify — отказ первый с момента
+// последнего разрешённого сообщения: о нём стоит сообщить пользователю.
+func (l *Limiter) Allow(key int64, now time.Time) (allowed, notify bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Minutes()*l.perMinute)
+	b.last = now
+
+	if b.tokens < 1 {
+		notify = !b.limited
+		b.limited = true
+		return false, notify
+	}
+	b.tokens--
+	b.limited = false
+	return true, false
+}
+
+// prune удаляет лимиты, которые к now восстановились полностью.
+func (l *Limiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Minutes()*l.perMinute >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
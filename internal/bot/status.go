@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/health"
+)
+
+// Пути служебного HTTP-сервера (STATUS_LISTEN).
+const (
+	MetricsPath = "/metrics"
+	HealthPath  = "/healthz"
+	ReadyPath   = "/readyz"
+)
+
+// MetricsHandler отдаёт метрики бота в текстовом формате Prometheus.
+func (t *TelegramBot) MetricsHandler() http.Handler {
+	return t.core.Metrics
+}
+
+// Health возвращает проверки готовности бота: база, Telegram (если бот подключён к нему)
+// и обработка обновлений.
+func (t *TelegramBot) Health() *health.Checker {
+	return t.health
+}
+
+// StatusHandler отдаёт метрики, /healthz и /readyz.
+func (t *TelegramBot) StatusHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, t.MetricsHandler())
+	mux.Handle(HealthPath, t.health.LiveHandler())
+	mux.Handle(ReadyPath, t.health.ReadyHandler())
+	return mux
+}
+
+// serve запускает HTTP-сервер name (служебный или веб-панель) на addr и возвращает функцию
+// его остановки. Занятый адрес — ошибка запуска, а не молча выключенные проверки.
+func serve(name, addr string, handler http.Handler) (stop func(), err error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP-сервер остановлен", "server", name, "error", err)
+		}
+	}()
+	slog.Info("HTTP-сервер запущен", "server", name, "listen", listener.Addr().String())
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+		<-done
+	}, nil
+}
+
+// newHealth собирает проверки готовности. Telegram проверяется, только если бот к нему подключён.
+func (t *TelegramBot) newHealth() *health.Checker {
+	checker := health.New()
+	checker.Add("storage", func(context.Context) error {
+		return t.core.Storage.Ping()
+	})
+	checker.Add("updates", func(context.Context) error {
+		return t.checkUpdates()
+	})
+	if t.api != nil {
+		checker.Add("telegram", func(context.Context) error {
+			return t.checkTelegram()
+		})
+	}
+	return checker
+}
+
+// checkUpdates сообщает о зависшей обработке: принятые обновления есть, но ни одно
+// не завершилось дольше READY_UPDATE_STALL.
+func (t *TelegramBot) checkUpdates() error {
+	stall := t.core.Config.ReadyUpdateStall
+	t.mu.Lock()
+	pending, since := len(t.pending), time.Since(t.lastProgress)
+	t.mu.Unlock()
+	if stall > 0 && pending > 0 && since > stall {
+		return fmt.Errorf("обновлений ждут обработки: %d, последнее обработано %s назад", pending, since.Round(time.Second))
+	}
+	return nil
+}
+
+// checkTelegram сообщает, что getMe не проходил дольше READY_TELEGRAM_MAX_AGE.
+func (t *TelegramBot) checkTelegram() error {
+	maxAge := t.core.Config.ReadyTelegramMaxAge
+	t.mu.Lock()
+	last, lastErr := t.telegramOK, t.telegramErr
+	t.mu.Unlock()
+	switch {
+	case last.IsZero():
+		return errors.New("getMe ещё не выполнен")
+	case maxAge > 0 && time.Since(last) > maxAge:
+		return fmt.Errorf("getMe не проходил %s: %v", time.Since(last).Round(time.Second), lastErr)
+	}
+	return nil
+}
+
+// markTelegram запоминает результат getMe для checkTelegram.
+func (t *TelegramBot) markTelegram(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil {
+		t.telegramErr = err
+		return
+	}
+	t.telegramOK, t.telegramErr = time.Now(), nil
+}
+
+// watchTelegram раз в TELEGRAM_CHECK_INTERVAL вызывает getMe, пока не отменён ctx.
+func (t *TelegramBot) watchTelegram(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, err := t.api.GetMe()
+		err = core.WithoutURL(err)
+		if err != nil {
+			slog.WarnContext(ctx, "Telegram не отвечает на getMe", "error", err)
+		}
+		t.markTelegram(err)
+	}
+}
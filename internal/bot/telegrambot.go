@@ -1,75 +1,626 @@
 package bot
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"telegram-anonymous-bot/internal/bot/core"
 	"telegram-anonymous-bot/internal/bot/handlers"
+	"telegram-anonymous-bot/internal/bot/middleware"
 	"telegram-anonymous-bot/internal/config"
+	"telegram-anonymous-bot/internal/crisis"
+	"telegram-anonymous-bot/internal/digest"
+	"telegram-anonymous-bot/internal/dispatch"
+	"telegram-anonymous-bot/internal/filter"
+	"telegram-anonymous-bot/internal/health"
+	"telegram-anonymous-bot/internal/llm"
+	"telegram-anonymous-bot/internal/metrics"
 	"telegram-anonymous-bot/internal/storage"
+	"telegram-anonymous-bot/internal/translate"
+	"telegram-anonymous-bot/internal/updatelog"
+	"telegram-anonymous-bot/internal/web"
 	"telegram-anonymous-bot/pkg/logger"
 )
 
 // TelegramBot — главный объект, регистрирующий хендлеры и обрабатывающий входящие команды.
 type TelegramBot struct {
-	core     *core.BotCore
-	handlers []handlers.CommandHandler
+	core *core.BotCore
+	// api — транспорт обновлений (getUpdates, webhook); сообщения отправляются через core.Messenger.
+	api       *tgbotapi.BotAPI
+	commands  *handlers.Registry
+	callbacks []handlers.CallbackHandler
+	// callbackMiddleware — общая цепочка для нажатий на кнопки (паника, лог, метрики).
+	callbackMiddleware []handlers.CallbackMiddleware
+	questions          handlers.HandlerFunc
+	digest             *handlers.DigestHandler
+	workers            *dispatch.Pool
+	// updateLog — журнал входящих обновлений (UPDATES_LOG); nil — не пишется.
+	updateLog *updatelog.Recorder
+	health    *health.Checker
+	// web — веб-панель администраторов (WEB_LISTEN); nil — выключена.
+	web *web.Server
+
+	// lastUpdateID — ID, до которого включительно все обновления обработаны (сохраняется в базе).
+	// pending — принятые, но ещё не обработанные обновления (канал закрывается по окончании
+	// обработки), maxSeen — наибольший принятый ID.
+	// lastProgress — когда последний раз завершилась обработка обновления или очередь
+	// перестала быть пустой. telegramOK — время последнего успешного getMe,
+	// telegramErr — ошибка getMe после него.
+	mu           sync.Mutex
+	lastUpdateID int
+	maxSeen      int
+	pending      map[int]chan struct{}
+	lastProgress time.Time
+	telegramOK   time.Time
+	telegramErr  error
 }
 
+// slowSubmit — после какого ожидания места в очереди обработчика писать в лог.
+const slowSubmit = time.Second
+
+const (
+	// pollTimeout — время ожидания long polling getUpdates в секундах.
+	pollTimeout = 60
+	// pollRetry — пауза перед повтором getUpdates после ошибки.
+	pollRetry = 3 * time.Second
+)
+
 func NewTelegramBot(cfg *config.Config, store storage.Storage) (*TelegramBot, error) {
-	botAPI, err := tgbotapi.NewBotAPI(cfg.TelegramBotToken)
+	endpoint := cfg.TelegramAPIEndpoint
+	if endpoint == "" {
+		endpoint = tgbotapi.APIEndpoint
+	}
+	botAPI, err := tgbotapi.NewBotAPIWithAPIEndpoint(cfg.TelegramBotToken, endpoint)
 	if err != nil {
-		return nil, err
+		return nil, core.WithoutURL(err)
 	}
 
 	botAPI.Debug = false
-	logger.InfoLogger.Printf("Авторизован бот: %s", botAPI.Self.UserName)
-
-	bc := &core.BotCore{
-		BotAPI:  botAPI,
-		Config:  cfg,
-		Storage: store,
-	}
-
-	return &TelegramBot{
-		core: bc,
-		handlers: []handlers.CommandHandler{
-			&handlers.StartHandler{Core: bc},
-			&handlers.AnswerHandler{Core: bc},
-			&handlers.ListHandler{Core: bc},
-			&handlers.MediaHandler{Core: bc},
-			&handlers.CohereHandler{Core: bc},
-			&handlers.HelpHandler{Core: bc},
-			// ... при необходимости добавляйте новые
-		},
+	slog.Info("Авторизован бот", "username", botAPI.Self.UserName)
+
+	bc, err := NewBotCore(cfg, store)
+	if err != nil {
+		return nil, err
+	}
+	t := NewTelegramBotWithCore(bc, botAPI)
+	// NewBotAPI уже проверил токен запросом getMe
+	t.markTelegram(nil)
+
+	if cfg.UpdatesLog != "" {
+		if t.updateLog, err = updatelog.Open(cfg.UpdatesLog, cfg.UpdatesLogRedact); err != nil {
+			return nil, err
+		}
+		slog.Info("Входящие обновления записываются в журнал", "path", cfg.UpdatesLog, "redact", cfg.UpdatesLogRedact)
+	}
+	return t, nil
+}
+
+// NewBotCore собирает BotCore по конфигурации: нейросеть, фильтр, кризисный детектор,
+// перевод и метрики запросов к базе и нейросети. Messenger не задаётся — его подставляет
+// NewTelegramBotWithCore или вызывающий.
+func NewBotCore(cfg *config.Config, store storage.Storage) (*core.BotCore, error) {
+	set := metrics.New()
+
+	httpClient, err := core.NewHTTPClient(cfg.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := llm.New(llm.Settings{
+		Provider:    cfg.LLMProvider,
+		APIKey:      cfg.LLMAPIKey,
+		BaseURL:     cfg.LLMBaseURL,
+		Model:       cfg.LLMModel,
+		MaxTokens:   cfg.LLMMaxTokens,
+		Temperature: cfg.LLMTemperature,
+	}, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("LLM-провайдер выбран", "provider", provider.Name())
+	provider = metrics.LLM(provider, set)
+
+	questionFilter, err := filter.Load(cfg.FilterRulesFile)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.FilterRulesFile != "" {
+		slog.Info("Правила фильтра загружены", "rules", questionFilter.Len())
+	}
+
+	crisisDetector, err := crisis.Load(cfg.CrisisRulesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var translator translate.Translator
+	if cfg.TranslateTo != "" {
+		if translator, err = translate.New(cfg.Translator, provider); err != nil {
+			return nil, err
+		}
+		slog.Info("Перевод вопросов включён", "target", cfg.TranslateTo)
+	}
+
+	return &core.BotCore{
+		Config:     cfg,
+		Storage:    metrics.Storage(store, set),
+		HTTPClient: httpClient,
+		LLM:        provider,
+		Filter:     questionFilter,
+		Crisis:     crisisDetector,
+		Translator: translator,
+		Metrics:    set,
 	}, nil
 }
 
-func (t *TelegramBot) Start() {
-	// Можно установить команды
-	// u := tgbotapi.NewUpdate(0)
-	// ...
+// NewTelegramBotWithCore регистрирует хендлеры поверх уже собранного BotCore.
+// api нужен только для приёма обновлений в Start; если bc.Messenger не задан,
+// сообщения отправляются через api. Каждая команда оборачивается цепочкой middleware: общие проверки для всех, затем
+// проверка объявленной роли для административных и ограничение частоты для остальных.
+// Запросы к Telegram учитываются в bc.Metrics; если он не задан, создаётся новый.
+// Если задан WEB_LISTEN, собирается веб-панель и команда /web для входа в неё.
+func NewTelegramBotWithCore(bc *core.BotCore, api *tgbotapi.BotAPI) *TelegramBot {
+	if bc.Messenger == nil && api != nil {
+		bc.Messenger = api
+	}
+	if bc.Metrics == nil {
+		bc.Metrics = metrics.New()
+	}
+	if bc.Messenger != nil {
+		bc.Messenger = metrics.TelegramMessenger(bc.Messenger, bc.Metrics)
+	}
+	limiter := middleware.NewLimiter(bc.Config.RateLimit, bc.Config.RateLimitBurst)
+	common := []handlers.Middleware{
+		middleware.Recover(bc),
+		middleware.Logging(),
+		middleware.Measure(bc.Metrics),
+		middleware.CheckBan(bc),
+	}
+
+	var panel *web.Server
+	if bc.Config.WebListen != "" {
+		var botName string
+		if api != nil {
+			botName = api.Self.UserName
+		}
+		panel = web.New(bc, botName)
+	}
+
+	registry := handlers.NewRegistry(bc)
+	digestHandler := &handlers.DigestHandler{Core: bc}
+	commandHandlers := []handlers.CommandHandler{
+		&handlers.StartHandler{Core: bc},
+		&handlers.AskHandler{Core: bc},
+		&handlers.NewChatHandler{Core: bc},
+		&handlers.LanguageHandler{Core: bc, Registry: registry},
+		&handlers.HelpHandler{Core: bc, Registry: registry},
+		&handlers.ListHandler{Core: bc},
+		&handlers.AnswerHandler{Core: bc},
+		&handlers.ReviewHandler{Core: bc},
+		&handlers.TemplateHandler{Core: bc},
+		&handlers.SuggestHandler{Core: bc},
+		digestHandler,
+		&handlers.FilterHandler{Core: bc},
+		&handlers.MediaHandler{Core: bc},
+		&handlers.BanHandler{Core: bc},
+		// ... при необходимости добавляйте новые
+	}
+	if panel != nil {
+		commandHandlers = append(commandHandlers, &handlers.WebHandler{Core: bc, Links: panel})
+	}
+	for _, h := range commandHandlers {
+		for _, cmd := range h.Commands() {
+			mws := slices.Clone(common)
+			if cmd.Role > core.RoleUser {
+				mws = append(mws, middleware.RequireRole(bc, cmd.Role))
+			} else {
+				mws = append(mws, middleware.RateLimit(bc, limiter))
+			}
+			registry.Register(cmd, mws...)
+		}
+	}
+
+	questions := &handlers.QuestionHandler{Core: bc}
+	workers := dispatch.New(bc.Config.Workers, bc.Config.WorkerQueueSize)
+	workers.OnBlocked = func(shard int, waited time.Duration) {
+		if waited >= slowSubmit {
+			slog.Warn("Очередь обработчика заполнена", "shard", shard, "waited", waited)
+		}
+	}
+	bc.Metrics.Workers(workers.Stats)
+	t := &TelegramBot{
+		core:     bc,
+		api:      api,
+		commands: registry,
+		callbacks: []handlers.CallbackHandler{
+			&handlers.TemplateCallbackHandler{Core: bc},
+			&handlers.SuggestCallbackHandler{Core: bc},
+			&handlers.ReleaseCallbackHandler{Core: bc},
+		},
+		callbackMiddleware: []handlers.CallbackMiddleware{
+			middleware.RecoverCallback(bc),
+			middleware.LoggingCallback(),
+			middleware.MeasureCallback(bc.Metrics),
+		},
+		questions: handlers.Wrap(questions.Handle, append(slices.Clone(common), middleware.RateLimit(bc, limiter))...),
+		digest:    digestHandler,
+		workers:   workers,
+		web:       panel,
+		pending:   make(map[int]chan struct{}),
+	}
+	t.health = t.newHealth()
+	return t
+}
+
+// Commands возвращает реестр команд бота.
+func (t *TelegramBot) Commands() *handlers.Registry {
+	return t.commands
+}
+
+// Core возвращает общее состояние бота, доступное хендлерам.
+func (t *TelegramBot) Core() *core.BotCore {
+	return t.core
+}
+
+// WorkerStats возвращает метрики очередей обработки обновлений.
+func (t *TelegramBot) WorkerStats() dispatch.Stats {
+	return t.workers.Stats()
+}
+
+// Start принимает обновления, пока не отменён ctx: через webhook, если задан WEBHOOK_URL,
+// иначе через long polling. Уже принятые обновления обрабатываются до конца в Shutdown.
+func (t *TelegramBot) Start(ctx context.Context) error {
+	defer t.workers.Close()
+
+	if addr := t.core.Config.StatusListen; addr != "" {
+		stop, err := serve("status", addr, t.StatusHandler())
+		if err != nil {
+			return fmt.Errorf("не удалось запустить служебный HTTP-сервер: %w", err)
+		}
+		defer stop()
+	}
+	if t.web != nil {
+		stop, err := serve("web", t.core.Config.WebListen, t.web)
+		if err != nil {
+			return fmt.Errorf("не удалось запустить веб-панель: %w", err)
+		}
+		defer stop()
+		slog.Info("Веб-панель доступна", "url", t.core.Config.WebURL)
+	}
+	if interval := t.core.Config.TelegramCheckEvery; t.api != nil && interval > 0 {
+		go t.watchTelegram(ctx, interval)
+	}
+
+	// Без меню бот работает, поэтому ошибка публикации не мешает запуску
+	if err := t.PublishCommands(ctx); err != nil {
+		slog.ErrorContext(ctx, "Меню команд не опубликовано", "error", err)
+	}
+	if t.core.Config.DigestTime != "" {
+		go t.runDailyDigest(ctx)
+	}
+
+	if t.core.Config.WebhookURL != "" {
+		return t.startWebhook(ctx)
+	}
+
+	// Оставшийся от прошлого запуска webhook мешает getUpdates
+	if err := t.deleteWebhook(); err != nil {
+		return err
+	}
+	return t.poll(ctx)
+}
+
+// poll получает обновления через getUpdates, начиная со следующего после последнего
+// обработанного. Telegram считает обновления полученными, как только getUpdates запрошен
+// с большим offset, поэтому следующая пачка запрашивается только после того, как вся
+// предыдущая поставлена в очередь: не попавшие в очередь обновления после перезапуска
+// будут получены снова, а поставленные Shutdown обрабатывает до SHUTDOWN_TIMEOUT.
+func (t *TelegramBot) poll(ctx context.Context) error {
+	last, err := t.core.Storage.GetLastUpdateID()
+	if err != nil {
+		return err
+	}
+	t.lastUpdateID = last
+
+	offset := last + 1
+	for {
+		updates, err := t.getUpdates(ctx, offset)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Ошибка getUpdates, повтор", "retry", pollRetry, "error", core.WithoutURL(err))
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(pollRetry):
+			}
+			continue
+		}
+		for _, update := range updates {
+			if !t.process(ctx, update) {
+				return nil
+			}
+			offset = update.UpdateID + 1
+		}
+	}
+}
+
+// getUpdates запрашивает обновления начиная с offset. После отмены ctx не ждёт ответа:
+// полученные этим запросом обновления ещё не подтверждены и придут снова.
+func (t *TelegramBot) getUpdates(ctx context.Context, offset int) ([]tgbotapi.Update, error) {
+	type result struct {
+		updates []tgbotapi.Update
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		updates, err := t.api.GetUpdates(tgbotapi.UpdateConfig{Offset: offset, Timeout: pollTimeout})
+		done <- result{updates, err}
+	}()
+	select {
+	case r := <-done:
+		return r.updates, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// process ставит обновление в очередь обработчика его чата: обновления одного чата
+// обрабатываются по порядку, разных чатов — параллельно. Если очередь заполнена, process ждёт,
+// пока не отменён ctx; false — обновление в очередь не попало.
+func (t *TelegramBot) process(ctx context.Context, update tgbotapi.Update) bool {
+	_, ok := t.enqueue(ctx, update, false)
+	return ok
+}
+
+// enqueue ставит обновление в очередь и возвращает канал, который закрывается после его
+// обработки, и false, если очередь закрыта или ctx отменён раньше, чем нашлось место. С dedup обновление, которое уже обработано
+// или обрабатывается (повторная доставка webhook), в очередь не ставится — возвращается
+// канал его обработки.
+func (t *TelegramBot) enqueue(ctx context.Context, update tgbotapi.Update, dedup bool) (<-chan struct{}, bool) {
+	id := update.UpdateID
+	t.mu.Lock()
+	if dedup {
+		if id <= t.lastUpdateID {
+			t.mu.Unlock()
+			handled := make(chan struct{})
+			close(handled)
+			return handled, true
+		}
+		if handled, ok := t.pending[id]; ok {
+			t.mu.Unlock()
+			return handled, true
+		}
+	}
+	handled := make(chan struct{})
+	// Уже сохранённые ID не отслеживаем: Telegram может начать нумерацию заново
+	track := id > t.lastUpdateID
+	if track {
+		if len(t.pending) == 0 {
+			t.lastProgress = time.Now()
+		}
+		t.pending[id] = handled
+		t.maxSeen = max(t.maxSeen, id)
+	}
+	t.mu.Unlock()
+
+	t.core.Metrics.Update(updateType(update))
+	if t.updateLog != nil {
+		if err := t.updateLog.Record(update); err != nil {
+			slog.Error("Не удалось записать обновление в журнал", "update_id", id, "error", err)
+		}
+	}
+
+	// Отклонённое при остановке обновление остаётся в pending, и отметка не перейдёт через него.
+	// Telegram его ещё не считает полученным: poll не запрашивает следующую пачку, пока эта
+	// не поставлена в очередь, а webhook не отвечает 200 — после перезапуска оно придёт снова
+	ok := t.workers.Submit(ctx, chatKey(update), func() {
+		defer close(handled)
+		if track {
+			defer t.done(id)
+		}
+		t.HandleUpdate(update)
+	})
+	return handled, ok
+}
+
+// updateType возвращает тип обновления для метрики metrics.UpdatesTotal.
+func updateType(update tgbotapi.Update) string {
+	switch {
+	case update.Message != nil:
+		return "message"
+	case update.EditedMessage != nil:
+		return "edited_message"
+	case update.CallbackQuery != nil:
+		return "callback_query"
+	default:
+		return "other"
+	}
+}
+
+// done отмечает обновление обработанным и сохраняет ID, до которого обработаны все принятые
+// обновления: при перезапуске незавершённые обновления будут получены снова.
+func (t *TelegramBot) done(id int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, id)
+	t.lastProgress = time.Now()
+
+	mark := t.maxSeen
+	for p := range t.pending {
+		mark = min(mark, p-1)
+	}
+	if mark <= t.lastUpdateID {
+		return
+	}
+	t.lastUpdateID = mark
+	if err := t.core.Storage.SetLastUpdateID(mark); err != nil {
+		core.LogStorageError(logger.With(context.Background(), "update_id", mark), "SetLastUpdateID", err)
+	}
+}
+
+// chatKey возвращает ключ очереди для обновления: ID чата, а если чата нет — ID отправителя.
+func chatKey(update tgbotapi.Update) int64 {
+	if chat := updateChat(update); chat != nil {
+		return chat.ID
+	}
+	if user := update.SentFrom(); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+// updateChat возвращает чат обновления. В отличие от Update.FromChat, не падает на нажатии
+// кнопки под inline-сообщением, у которого нет Message.
+func updateChat(update tgbotapi.Update) *tgbotapi.Chat {
+	if cb := update.CallbackQuery; cb != nil {
+		if cb.Message == nil {
+			return nil
+		}
+		return cb.Message.Chat
+	}
+	return update.FromChat()
+}
 
-	updates := t.core.BotAPI.GetUpdatesChan(tgbotapi.UpdateConfig{
-		Offset:  0,
-		Timeout: 60,
+// Shutdown дожидается обработки уже принятых обновлений и фоновых задач (классификация,
+// перевод и т.п.) не дольше timeout и закрывает журнал обновлений. Вызывается после возврата
+// из Start; хранилище закрывает вызывающий.
+func (t *TelegramBot) Shutdown(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	t.workers.Close()
+	if !t.workers.Wait(timeout) {
+		slog.Error("Не все обновления обработаны", "timeout", timeout)
+	}
+	if !t.core.WaitTimeout(time.Until(deadline)) {
+		slog.Error("Не все фоновые задачи завершились", "timeout", timeout)
+	}
+	if t.updateLog != nil {
+		if err := t.updateLog.Close(); err != nil {
+			slog.Error("Ошибка закрытия журнала обновлений", "error", err)
+		}
+	}
+}
+
+// Replay прогоняет обновления из JSONL-журнала через те же очереди обработчиков, что и
+// живые обновления, но не сохраняет их ID. Возвращает число поставленных в очередь
+// обновлений; дождаться их обработки — Shutdown.
+func (t *TelegramBot) Replay(r io.Reader) (int, error) {
+	n := 0
+	err := updatelog.Read(r, func(update tgbotapi.Update) error {
+		t.workers.Submit(context.Background(), chatKey(update), func() { t.HandleUpdate(update) })
+		n++
+		return nil
 	})
+	return n, err
+}
+
+// HandleUpdate разбирает одно входящее обновление: сообщение или нажатие inline-кнопки.
+// Все строки журнала при его обработке помечаются ID обновления, чатом и командой.
+func (t *TelegramBot) HandleUpdate(update tgbotapi.Update) {
+	ctx := updateContext(update)
+	switch {
+	case update.Message != nil:
+		t.handleMessage(ctx, update.Message)
+	case update.CallbackQuery != nil:
+		t.handleCallback(ctx, update.CallbackQuery)
+	}
+}
 
-	for update := range updates {
-		if update.Message != nil && update.Message.IsCommand() {
-			t.handleCommand(update.Message)
+// HandleMessage обрабатывает сообщение вне обновления (в тестах и консоли).
+func (t *TelegramBot) HandleMessage(msg *tgbotapi.Message) {
+	t.handleMessage(updateContext(tgbotapi.Update{Message: msg}), msg)
+}
+
+// updateContext возвращает context с атрибутами журнала для обновления. ID отправителя
+// и чата хешируются: по журналу нельзя узнать, кто задал вопрос.
+func updateContext(update tgbotapi.Update) context.Context {
+	args := []any{"update_id", update.UpdateID}
+	if chat := updateChat(update); chat != nil {
+		args = append(args, "chat", logger.ID(chat.ID))
+	}
+	if user := update.SentFrom(); user != nil {
+		args = append(args, "user", logger.ID(user.ID))
+	}
+	switch {
+	case update.Message != nil && update.Message.IsCommand():
+		args = append(args, "command", update.Message.Command())
+	case update.CallbackQuery != nil:
+		args = append(args, "callback", update.CallbackQuery.Data)
+	}
+	return logger.With(context.Background(), args...)
+}
+
+// handleMessage направляет команды в router, а остальные сообщения считает вопросами.
+func (t *TelegramBot) handleMessage(ctx context.Context, msg *tgbotapi.Message) {
+	if msg.From == nil {
+		return
+	}
+	if msg.IsCommand() {
+		t.handleCommand(ctx, msg)
+		return
+	}
+	t.questions(ctx, msg)
+}
+
+// handleCommand находит команду в реестре по имени или псевдониму и выполняет её.
+func (t *TelegramBot) handleCommand(ctx context.Context, msg *tgbotapi.Message) {
+	if handle, ok := t.commands.Lookup(msg.Command()); ok {
+		handle(ctx, msg)
+		return
+	}
+	t.core.Reply(ctx, msg, "common.unknown_command")
+}
+
+// handleCallback ищет CallbackHandler по данным нажатой inline-кнопки и вызывает его
+// внутри цепочки callbackMiddleware.
+func (t *TelegramBot) handleCallback(ctx context.Context, cb *tgbotapi.CallbackQuery) {
+	for _, h := range t.callbacks {
+		if h.CanHandleCallback(cb.Data) {
+			handlers.WrapCallback(h.HandleCallback, t.callbackMiddleware...)(ctx, cb)
+			return
 		}
-		// Или обрабатывать CallbackQuery/просто текст
 	}
+	t.core.AnswerCallback(ctx, cb, "common.unknown_button")
 }
 
-// handleCommand ищет подходящий CommandHandler и вызывает его метод Handle(msg).
-func (t *TelegramBot) handleCommand(msg *tgbotapi.Message) {
-	cmd := msg.Command()
-	for _, h := range t.handlers {
-		if h.CanHandle(cmd) {
-			h.Handle(msg)
+// runDailyDigest каждый день в DIGEST_TIME отправляет администраторам сводку неотвеченных вопросов.
+// Сводка готовится один раз на каждый язык администраторов. Завершается при отмене ctx.
+func (t *TelegramBot) runDailyDigest(ctx context.Context) {
+	for {
+		next, err := digest.NextRun(time.Now(), t.core.Config.DigestTime)
+		if err != nil {
+			slog.ErrorContext(ctx, "Дайджест выключен", "error", err)
 			return
 		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		reports := make(map[string]string)
+		for _, adminID := range t.core.AdminIDs() {
+			lang := t.core.UserLang(ctx, adminID, "")
+			report, ok := reports[lang]
+			if !ok {
+				if report, err = t.digest.Report(ctx, time.Time{}, lang); err != nil {
+					slog.ErrorContext(ctx, "Ошибка подготовки дайджеста", "error", err)
+					break
+				}
+				reports[lang] = report
+			}
+			for _, part := range core.SplitMessage(report, core.MaxMessageLength) {
+				t.core.SendMessage(ctx, adminID, part)
+			}
+		}
 	}
-	t.core.SendMessage(msg.Chat.ID, "Неизвестная команда (через общий router).")
 }
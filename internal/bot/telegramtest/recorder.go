@@ -0,0 +1,187 @@
+// Package telegramtest — записывающая подделка Bot API для тестов хендлеров.
+package telegramtest
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Call — один запрос к Bot API, записанный Recorder.
+type Call struct {
+	// Method — метод Bot API: sendMessage, sendPhoto, editMessageText и т.д.
+	Method string
+	ChatID int64
+	// MessageID — редактируемое сообщение (для правок).
+	MessageID int
+	// Text — текст сообщения или правки, подпись к медиа, текст ответа на callback.
+	Text string
+	// FileID — файл фото или видео.
+	FileID string
+	// Config — исходный запрос для проверок, которых нет в полях выше (только у Recorder).
+	Config tgbotapi.Chattable
+	// Params — параметры HTTP-запроса (только у Server).
+	Params url.Values
+}
+
+// journal — журнал запросов с выборками, общий для Recorder и Server.
+type journal struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (j *journal) add(c Call) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.calls = append(j.calls, c)
+}
+
+// Calls возвращает записанные запросы метода method; пустой method — все запросы.
+func (j *journal) Calls(method string) []Call {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var calls []Call
+	for _, c := range j.calls {
+		if method == "" || c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Messages возвращает тексты сообщений, отправленных в чат.
+func (j *journal) Messages(chatID int64) []string {
+	return j.texts("sendMessage", chatID)
+}
+
+// Edits возвращает тексты правок сообщений в чате.
+func (j *journal) Edits(chatID int64) []string {
+	return j.texts("editMessageText", chatID)
+}
+
+// Media возвращает фото и видео, отправленные в чат.
+func (j *journal) Media(chatID int64) []Call {
+	var media []Call
+	for _, c := range j.Calls("") {
+		if (c.Method == "sendPhoto" || c.Method == "sendVideo") && c.ChatID == chatID {
+			media = append(media, c)
+		}
+	}
+	return media
+}
+
+func (j *journal) texts(method string, chatID int64) []string {
+	var texts []string
+	for _, c := range j.Calls(method) {
+		if c.ChatID == chatID {
+			texts = append(texts, c.Text)
+		}
+	}
+	return texts
+}
+
+// Recorder реализует core.Messenger: запоминает все запросы и отвечает успехом.
+type Recorder struct {
+	// Hook, если задан, вызывается перед записью каждого запроса — например, чтобы
+	// задержать его или вернуть ошибку (тогда запрос не записывается).
+	Hook func(c Call) error
+
+	journal
+	mu        sync.Mutex
+	messageID int
+}
+
+// boolMethods — методы Bot API, которые вместо сообщения возвращают true. tgbotapi Send
+// не может разобрать такой ответ и возвращает ошибку, хотя запрос выполнен: их нужно
+// вызывать через Request.
+var boolMethods = map[string]bool{
+	"sendChatAction":      true,
+	"answerCallbackQuery": true,
+	"setMyCommands":       true,
+	"deleteMyCommands":    true,
+}
+
+// Send записывает запрос и возвращает сообщение с новым ID. Для методов, которые
+// не возвращают сообщение, Send, как и в tgbotapi, возвращает ошибку.
+func (r *Recorder) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	call, err := r.record(c)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+	if boolMethods[call.Method] {
+		return tgbotapi.Message{}, fmt.Errorf("%s: json: cannot unmarshal bool into Go value of type tgbotapi.Message (используйте Request)", call.Method)
+	}
+	return tgbotapi.Message{MessageID: call.MessageID, Chat: &tgbotapi.Chat{ID: call.ChatID}, Text: call.Text}, nil
+}
+
+// Request записывает служебный запрос.
+func (r *Recorder) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if _, err := r.record(c); err != nil {
+		return nil, err
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (r *Recorder) record(c tgbotapi.Chattable) (Call, error) {
+	call := describe(c)
+	if r.Hook != nil {
+		if err := r.Hook(call); err != nil {
+			return call, err
+		}
+	}
+
+	r.mu.Lock()
+	if call.MessageID == 0 {
+		r.messageID++
+		call.MessageID = r.messageID
+	}
+	r.mu.Unlock()
+	r.add(call)
+	return call, nil
+}
+
+// describe раскладывает запрос по полям Call.
+func describe(c tgbotapi.Chattable) Call {
+	call := Call{Config: c}
+	switch c := c.(type) {
+	case tgbotapi.MessageConfig:
+		call.Method, call.ChatID, call.Text = "sendMessage", c.ChatID, c.Text
+	case tgbotapi.PhotoConfig:
+		call.Method, call.ChatID, call.Text = "sendPhoto", c.ChatID, c.Caption
+		call.FileID = fileID(c.File)
+	case tgbotapi.VideoConfig:
+		call.Method, call.ChatID, call.Text = "sendVideo", c.ChatID, c.Caption
+		call.FileID = fileID(c.File)
+	case tgbotapi.EditMessageTextConfig:
+		call.Method, call.ChatID, call.MessageID, call.Text = "editMessageText", c.ChatID, c.MessageID, c.Text
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		call.Method, call.ChatID, call.MessageID = "editMessageReplyMarkup", c.ChatID, c.MessageID
+	case tgbotapi.ChatActionConfig:
+		call.Method, call.ChatID, call.Text = "sendChatAction", c.ChatID, c.Action
+	case tgbotapi.CallbackConfig:
+		call.Method, call.Text = "answerCallbackQuery", c.Text
+	case tgbotapi.SetMyCommandsConfig:
+		call.Method = "setMyCommands"
+		if c.Scope != nil {
+			call.ChatID = c.Scope.ChatID
+		}
+	case tgbotapi.DeleteMyCommandsConfig:
+		call.Method = "deleteMyCommands"
+		if c.Scope != nil {
+			call.ChatID = c.Scope.ChatID
+		}
+	default:
+		call.Method = fmt.Sprintf("%T", c)
+	}
+	return call
+}
+
+func fileID(file tgbotapi.RequestFileData) string {
+	if id, ok := file.(tgbotapi.FileID); ok {
+		return string(id)
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package telegramtest_test
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/telegramtest"
+)
+
+func TestRecorder(t *testing.T) {
+	r := &telegramtest.Recorder{}
+
+	first, _ := r.Send(tgbotapi.NewMessage(1, "привет"))
+	second, _ := r.Send(tgbotapi.NewMessage(2, "другой чат"))
+	if first.MessageID == second.MessageID {
+		t.Errorf("Expected distinct message IDs, got %d", first.MessageID)
+	}
+	r.Send(tgbotapi.NewEditMessageText(1, first.MessageID, "привет!"))
+	r.Send(tgbotapi.NewVideo(1, tgbotapi.FileID("video-1")))
+	r.Request(tgbotapi.NewCallback("cb", "готово"))
+
+	if got := r.Messages(1); len(got) != 1 || got[0] != "привет" {
+		t.Errorf("Unexpected messages: %v", got)
+	}
+	if got := r.Edits(1); len(got) != 1 || got[0] != "привет!" {
+		t.Errorf("Unexpected edits: %v", got)
+	}
+	if got := r.Media(1); len(got) != 1 || got[0].Method != "sendVideo" || got[0].FileID != "video-1" {
+		t.Errorf("Unexpected media: %+v", got)
+	}
+	if got := r.Calls("answerCallbackQuery"); len(got) != 1 || got[0].Text != "готово" {
+		t.Errorf("Unexpected callback answers: %+v", got)
+	}
+	if got := r.Calls(""); len(got) != 5 {
+		t.Errorf("Expected 5 calls, got %d", len(got))
+	}
+}
+
+func TestRecorderSendOfBoolMethod(t *testing.T) {
+	r := &telegramtest.Recorder{}
+
+	// Как и в tgbotapi, Send не разбирает ответ true: такие методы вызываются через Request
+	if _, err := r.Send(tgbotapi.NewChatAction(1, tgbotapi.ChatTyping)); err == nil {
+		t.Error("Expected Send of sendChatAction to fail")
+	}
+	if _, err := r.Request(tgbotapi.NewChatAction(1, tgbotapi.ChatTyping)); err != nil {
+		t.Errorf("Expected Request of sendChatAction to succeed, got %v", err)
+	}
+}
+
+func TestRecorderHookError(t *testing.T) {
+	r := &telegramtest.Recorder{Hook: func(c telegramtest.Call) error {
+		if c.ChatID == 403 {
+			return errors.New("Forbidden: bot was blocked by the user")
+		}
+		return nil
+	}}
+
+	if _, err := r.Send(tgbotapi.NewMessage(403, "привет")); err == nil {
+		t.Errorf("Expected hook error")
+	}
+	if got := r.Messages(403); len(got) != 0 {
+		t.Errorf("Failed sends must not be recorded, got %v", got)
+	}
+}
@@ -0,0 +1,246 @@
+package telegramtest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// BotUser — бот, которым представляется Server в ответ на getMe.
+var BotUser = tgbotapi.User{ID: 1, IsBot: true, FirstName: "Test", UserName: "test_bot"}
+
+// Server — локальная подделка Bot API для сквозных тестов: настоящий клиент tgbotapi
+// ходит к нему по HTTP. Тест добавляет обновления (PushUpdate, PushText), которые бот
+// получает через getUpdates, и проверяет отправленное бот (Calls, Messages, Edits, Media).
+type Server struct {
+	journal
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	updates   []tgbotapi.Update
+	updateID  int
+	messageID int
+	// files — содержимое файлов по file_id для getFile и скачивания (AddFile).
+	files map[string][]byte
+	// arrived закрывается и пересоздаётся при каждом новом обновлении — будит ожидающий getUpdates.
+	arrived chan struct{}
+	closed  chan struct{}
+}
+
+// NewServer запускает сервер; остановить его нужно методом Close.
+func NewServer() *Server {
+	s := &Server{arrived: make(chan struct{}), closed: make(chan struct{}), files: make(map[string][]byte)}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Endpoint возвращает шаблон адреса API для tgbotapi.NewBotAPIWithAPIEndpoint.
+func (s *Server) Endpoint() string {
+	return s.srv.URL + "/bot%s/%s"
+}
+
+// Close прерывает ожидающие getUpdates и останавливает сервер.
+func (s *Server) Close() {
+	close(s.closed)
+	s.srv.Close()
+}
+
+// AddFile делает файл fileID доступным: getFile вернёт путь к нему, а по этому пути
+// отдаётся content.
+func (s *Server) AddFile(fileID string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[fileID] = content
+}
+
+// PushUpdate добавляет обновление в очередь getUpdates и возвращает присвоенный ему UpdateID.
+func (s *Server) PushUpdate(u tgbotapi.Update) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updateID++
+	u.UpdateID = s.updateID
+	s.updates = append(s.updates, u)
+	close(s.arrived)
+	s.arrived = make(chan struct{})
+	return u.UpdateID
+}
+
+// PushText добавляет сообщение пользователя userID в личном чате с ботом.
+// Команда в начале текста размечается, как это делает Telegram.
+func (s *Server) PushText(userID int64, text string) int {
+	s.mu.Lock()
+	s.messageID++
+	msg := &tgbotapi.Message{
+		MessageID: s.messageID,
+		From:      &tgbotapi.User{ID: userID},
+		Chat:      &tgbotapi.Chat{ID: userID, Type: "private"},
+		Date:      int(time.Now().Unix()),
+		Text:      text,
+	}
+	s.mu.Unlock()
+
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		msg.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len([]rune(command))}}
+	}
+	return s.PushUpdate(tgbotapi.Update{Message: msg})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/file/") {
+		s.serveFile(w, r)
+		return
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		reply(w, http.StatusBadRequest, nil, err.Error())
+		return
+	}
+	method := path.Base(r.URL.Path)
+	params := r.Form
+	call := Call{Method: method, Params: params, Text: params.Get("text")}
+	call.ChatID, _ = strconv.ParseInt(params.Get("chat_id"), 10, 64)
+	call.MessageID, _ = strconv.Atoi(params.Get("message_id"))
+
+	var result any
+	switch method {
+	case "getMe":
+		result = BotUser
+	case "getUpdates":
+		result = s.pendingUpdates(params)
+	case "sendMessage":
+		result = s.message(call)
+	case "sendPhoto", "sendVideo":
+		field := strings.ToLower(strings.TrimPrefix(method, "send"))
+		call.FileID, call.Text = params.Get(field), params.Get("caption")
+		if call.FileID == "" && r.MultipartForm != nil {
+			if files := r.MultipartForm.File[field]; len(files) > 0 {
+				call.FileID = files[0].Filename
+			}
+		}
+		result = s.message(call)
+	case "copyMessage":
+		// MessageID у копии — исходное сообщение, как у правок
+		result = tgbotapi.MessageID{MessageID: s.message(call).MessageID}
+	case "editMessageText":
+		result = tgbotapi.Message{MessageID: call.MessageID, Chat: &tgbotapi.Chat{ID: call.ChatID}, Text: call.Text}
+	case "getFile":
+		s.mu.Lock()
+		content, ok := s.files[params.Get("file_id")]
+		s.mu.Unlock()
+		if !ok {
+			s.add(call)
+			reply(w, http.StatusBadRequest, nil, "Bad Request: invalid file_id")
+			return
+		}
+		result = tgbotapi.File{FileID: params.Get("file_id"), FileSize: len(content), FilePath: filePath(params.Get("file_id"))}
+	case "answerCallbackQuery":
+		result = true
+	case "setMyCommands", "deleteMyCommands":
+		var scope tgbotapi.BotCommandScope
+		_ = json.Unmarshal([]byte(params.Get("scope")), &scope)
+		call.ChatID = scope.ChatID
+		result = true
+	case "deleteWebhook", "sendChatAction", "editMessageReplyMarkup":
+		result = true
+	default:
+		s.add(call)
+		reply(w, http.StatusNotFound, nil, "Not Found: method "+method+" is not supported by telegramtest.Server")
+		return
+	}
+	if method != "getUpdates" && method != "getMe" {
+		s.add(call)
+	}
+	reply(w, http.StatusOK, result, "")
+}
+
+// serveFile отдаёт файл, добавленный AddFile, по пути /file/bot<токен>/<путь из getFile>.
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for fileID, content := range s.files {
+		if strings.HasSuffix(r.URL.Path, "/"+filePath(fileID)) {
+			_, _ = w.Write(content)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func filePath(fileID string) string {
+	return "files/" + fileID
+}
+
+// message возвращает отправленное ботом сообщение с новым MessageID.
+func (s *Server) message(call Call) tgbotapi.Message {
+	s.mu.Lock()
+	s.messageID++
+	id := s.messageID
+	s.mu.Unlock()
+
+	return tgbotapi.Message{
+		MessageID: id,
+		From:      &BotUser,
+		Chat:      &tgbotapi.Chat{ID: call.ChatID, Type: "private"},
+		Date:      int(time.Now().Unix()),
+		Text:      call.Text,
+	}
+}
+
+// pendingUpdates возвращает обновления начиная с offset. Если их нет, ждёт новых до timeout
+// секунд, как long polling Telegram, — но не дольше, чем до Close. Как и Telegram, сервер
+// забывает обновления с ID меньше offset: запрос с таким offset их подтверждает.
+func (s *Server) pendingUpdates(params url.Values) []tgbotapi.Update {
+	offset, _ := strconv.Atoi(params.Get("offset"))
+	timeout, _ := strconv.Atoi(params.Get("timeout"))
+	deadline := time.After(time.Duration(timeout) * time.Second)
+	s.mu.Lock()
+	s.updates = slices.DeleteFunc(s.updates, func(u tgbotapi.Update) bool { return u.UpdateID < offset })
+	s.mu.Unlock()
+	for {
+		s.mu.Lock()
+		updates := []tgbotapi.Update{}
+		for _, u := range s.updates {
+			if u.UpdateID >= offset {
+				updates = append(updates, u)
+			}
+		}
+		arrived := s.arrived
+		s.mu.Unlock()
+
+		if len(updates) > 0 || timeout == 0 {
+			return updates
+		}
+		select {
+		case <-arrived:
+		case <-deadline:
+			return updates
+		case <-s.closed:
+			return updates
+		}
+	}
+}
+
+// reply пишет ответ в формате Bot API.
+func reply(w http.ResponseWriter, status int, result any, description string) {
+	resp := map[string]any{"ok": status == http.StatusOK}
+	if status == http.StatusOK {
+		resp["result"] = result
+	} else {
+		resp["error_code"] = status
+		resp["description"] = description
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,59 @@
+package telegramtest_test
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/telegramtest"
+)
+
+func TestServer(t *testing.T) {
+	srv := telegramtest.NewServer()
+	defer srv.Close()
+
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint("fake_token", srv.Endpoint())
+	if err != nil {
+		t.Fatalf("NewBotAPIWithAPIEndpoint failed: %v", err)
+	}
+	if api.Self.UserName != telegramtest.BotUser.UserName {
+		t.Errorf("Unexpected bot user %+v", api.Self)
+	}
+
+	first := srv.PushText(1, "/start")
+	srv.PushText(1, "вопрос")
+	updates, err := api.GetUpdates(tgbotapi.UpdateConfig{Offset: first + 1})
+	if err != nil || len(updates) != 1 || updates[0].Message.Text != "вопрос" {
+		t.Fatalf("Expected updates after offset, got %v (%v)", updates, err)
+	}
+
+	sent, err := api.Send(tgbotapi.NewMessage(1, "привет"))
+	if err != nil || sent.MessageID == 0 {
+		t.Fatalf("sendMessage failed: %v", err)
+	}
+	api.Send(tgbotapi.NewEditMessageText(1, sent.MessageID, "привет!"))
+	photo := tgbotapi.NewPhoto(2, tgbotapi.FileID("photo-1"))
+	photo.Caption = "подпись"
+	api.Send(photo)
+	if _, err := api.CopyMessage(tgbotapi.NewCopyMessage(2, 1, sent.MessageID)); err != nil {
+		t.Errorf("copyMessage failed: %v", err)
+	}
+	if _, err := api.Request(tgbotapi.NewCallback("cb", "готово")); err != nil {
+		t.Errorf("answerCallbackQuery failed: %v", err)
+	}
+	if _, err := api.Request(tgbotapi.NewChatTitle(1, "чат")); err == nil {
+		t.Errorf("Expected unsupported method to fail")
+	}
+
+	if got := srv.Messages(1); len(got) != 1 || got[0] != "привет" {
+		t.Errorf("Unexpected messages: %v", got)
+	}
+	if got := srv.Edits(1); len(got) != 1 || got[0] != "привет!" {
+		t.Errorf("Unexpected edits: %v", got)
+	}
+	if got := srv.Media(2); len(got) != 1 || got[0].FileID != "photo-1" || got[0].Text != "подпись" {
+		t.Errorf("Unexpected media: %+v", got)
+	}
+	if got := srv.Calls("copyMessage"); len(got) != 1 || got[0].ChatID != 2 || got[0].MessageID != sent.MessageID {
+		t.Errorf("Unexpected copies: %+v", got)
+	}
+}
@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/bot/core"
+)
+
+const (
+	// SecretTokenHeader — заголовок, в котором Telegram присылает секрет, заданный в setWebhook.
+	SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// maxUpdateSize ограничивает размер тела запроса с обновлением.
+	maxUpdateSize = 1 << 20
+	// webhookWait — сколько webhook ждёт обработки обновления, прежде чем ответить Telegram
+	// ошибкой: Telegram доставит обновление снова, и повтор дождётся той же обработки.
+	webhookWait = 30 * time.Second
+)
+
+// WebhookHandler принимает обновления от Telegram и ставит их в ту же очередь обработки,
+// что и long polling. Если secret не пуст, запросы без верного
+// заголовка X-Telegram-Bot-Api-Secret-Token отклоняются.
+//
+// Telegram получает ответ 200 только после обработки обновления, поэтому необработанное
+// обновление будет доставлено снова. Повторные доставки уже обработанных обновлений
+// (update_id не больше сохранённого) подтверждаются без повторной обработки.
+func (t *TelegramBot) WebhookHandler(secret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(SecretTokenHeader)), []byte(secret)) != 1 {
+			slog.WarnContext(r.Context(), "Webhook: запрос с неверным секретом", "remote", r.RemoteAddr)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUpdateSize)).Decode(&update); err != nil {
+			http.Error(w, "bad update: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Не r.Context(): обновление, отклонённое из-за обрыва соединения, осталось бы в
+		// pending навсегда; ждущий места запрос отпускает Close при остановке
+		handled, ok := t.enqueue(context.Background(), update, true)
+		if !ok {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		timer := time.NewTimer(webhookWait)
+		defer timer.Stop()
+		select {
+		case <-handled:
+			w.WriteHeader(http.StatusOK)
+		case <-timer.C:
+			http.Error(w, "update is still being handled", http.StatusServiceUnavailable)
+		case <-r.Context().Done():
+		}
+	})
+}
+
+// startWebhook регистрирует webhook в Telegram и принимает обновления встроенным
+// HTTP(S)-сервером, пока не отменён ctx. При остановке сервер ждёт начатые запросы
+// не дольше ShutdownTimeout, после чего webhook снимается.
+func (t *TelegramBot) startWebhook(ctx context.Context) error {
+	cfg := t.core.Config
+
+	path := cfg.WebhookPath
+	if path == "" {
+		path = "/"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, t.WebhookHandler(cfg.WebhookSecret))
+	server := &http.Server{Addr: cfg.WebhookListen, Handler: mux}
+
+	last, err := t.core.Storage.GetLastUpdateID()
+	if err != nil {
+		return err
+	}
+	t.lastUpdateID = last
+
+	if err := t.setWebhook(); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "Webhook зарегистрирован", "url", cfg.WebhookURL, "listen", cfg.WebhookListen, "path", path)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if cfg.WebhookCertFile != "" {
+			serveErr <- server.ListenAndServeTLS(cfg.WebhookCertFile, cfg.WebhookKeyFile)
+		} else {
+			serveErr <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		if err := server.Shutdown(drainCtx); err != nil {
+			slog.ErrorContext(ctx, "Webhook-сервер остановлен без ожидания запросов", "error", err)
+			server.Close()
+		}
+		cancel()
+		err = <-serveErr
+	}
+
+	if err := t.deleteWebhook(); err != nil {
+		slog.ErrorContext(ctx, "Webhook не снят", "error", err)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// setWebhook сообщает Telegram адрес webhook и секрет. Самоподписанный сертификат
+// (WEBHOOK_CERT_FILE) загружается вместе с запросом.
+func (t *TelegramBot) setWebhook() error {
+	cfg := t.core.Config
+	if _, err := url.ParseRequestURI(cfg.WebhookURL); err != nil {
+		return fmt.Errorf("некорректный WEBHOOK_URL: %w", err)
+	}
+
+	params := tgbotapi.Params{"url": cfg.WebhookURL}
+	params.AddNonEmpty("secret_token", cfg.WebhookSecret)
+
+	var err error
+	if cfg.WebhookCertFile != "" && cfg.WebhookUploadCert {
+		_, err = t.api.UploadFiles("setWebhook", params, []tgbotapi.RequestFile{{
+			Name: "certificate",
+			Data: tgbotapi.FilePath(cfg.WebhookCertFile),
+		}})
+	} else {
+		_, err = t.api.MakeRequest("setWebhook", params)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка setWebhook: %w", core.WithoutURL(err))
+	}
+	return nil
+}
+
+// deleteWebhook отключает webhook: без этого Telegram не отдаёт обновления через getUpdates.
+func (t *TelegramBot) deleteWebhook() error {
+	if _, err := t.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return fmt.Errorf("ошибка deleteWebhook: %w", core.WithoutURL(err))
+	}
+	return nil
+}
@@ -0,0 +1,372 @@
+package bot_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/mock"
+	"telegram-anonymous-bot/internal/bot"
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/bot/telegramtest"
+	"telegram-anonymous-bot/internal/config"
+	"telegram-anonymous-bot/internal/dispatch"
+	"telegram-anonymous-bot/internal/health"
+	"telegram-anonymous-bot/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init()
+	os.Exit(m.Run())
+}
+
+// stubClient подменяет HTTP-клиент tgbotapi для проверки транспорта (getUpdates, webhook):
+// запоминает вызовы и всегда отвечает успехом.
+type stubClient struct {
+	mu    sync.Mutex
+	calls []url.Values
+	// updates — пачки обновлений (JSON-массивы), которые по очереди вернёт getUpdates.
+	updates []string
+}
+
+func (c *stubClient) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	values, _ := url.ParseQuery(string(body))
+	method := path.Base(req.URL.Path)
+	values.Set("method", method)
+
+	c.mu.Lock()
+	c.calls = append(c.calls, values)
+	result := "true"
+	switch method {
+	case "getMe":
+		result = `{"id":1,"is_bot":true,"username":"test_bot"}`
+	case "getUpdates":
+		result = "[]"
+		if len(c.updates) > 0 {
+			result, c.updates = c.updates[0], c.updates[1:]
+		}
+	}
+	c.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":` + result + `}`)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+// callsTo возвращает параметры всех вызовов метода Bot API.
+func (c *stubClient) callsTo(method string) []map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var calls []map[string]string
+	for _, v := range c.calls {
+		if v.Get("method") == method {
+			call := make(map[string]string)
+			for key := range v {
+				call[key] = v.Get(key)
+			}
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+const startUpdate = `{"update_id": 1, "message": {"message_id": 1, "text": "/start",
+	"from": {"id": 12345}, "chat": {"id": 12345},
+	"entities": [{"type": "bot_command", "offset": 0, "length": 6}]}}`
+
+func TestWebhookHandler(t *testing.T) {
+	storageMock := new(MockStorage)
+	storageMock.On("SetLastUpdateID", 1).Return(nil)
+	telegramBot, tg := newTestBot(storageMock)
+	handler := telegramBot.WebhookHandler("s3cret")
+
+	tests := []struct {
+		name   string
+		method string
+		secret string
+		body   string
+		want   int
+	}{
+		{name: "wrong method", method: http.MethodGet, secret: "s3cret", want: http.StatusMethodNotAllowed},
+		{name: "missing secret", method: http.MethodPost, body: startUpdate, want: http.StatusForbidden},
+		{name: "wrong secret", method: http.MethodPost, secret: "guess", body: startUpdate, want: http.StatusForbidden},
+		{name: "bad json", method: http.MethodPost, secret: "s3cret", body: "{", want: http.StatusBadRequest},
+		{name: "update", method: http.MethodPost, secret: "s3cret", body: startUpdate, want: http.StatusOK},
+		{name: "redelivery", method: http.MethodPost, secret: "s3cret", body: startUpdate, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/webhook", strings.NewReader(tt.body))
+			if tt.secret != "" {
+				req.Header.Set(bot.SecretTokenHeader, tt.secret)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d (%s)", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	// Обновление обработано тем же кодом, что и при long polling, — и только один раз,
+	// а ответ Telegram получил уже после обработки
+	if got := tg.Messages(12345); len(got) != 1 {
+		t.Errorf("Expected the update to be handled before the response, got %v", got)
+	}
+	telegramBot.Shutdown(time.Second)
+	if got := tg.Messages(12345); len(got) != 1 || !strings.Contains(got[0], "Привет") {
+		t.Errorf("Expected a single greeting, got %v", got)
+	}
+	storageMock.AssertCalled(t, "SetLastUpdateID", 1)
+}
+
+func TestWebhookStartStop(t *testing.T) {
+	client := &stubClient{}
+	api := &tgbotapi.BotAPI{Token: "fake_token", Client: client}
+	api.SetAPIEndpoint(tgbotapi.APIEndpoint)
+	storageMock := new(MockStorage)
+	storageMock.On("GetLastUpdateID").Return(0, nil)
+	storageMock.On("GetState", mock.Anything).Return("", nil)
+	storageMock.On("SetState", mock.Anything, mock.Anything).Return(nil)
+
+	telegramBot := bot.NewTelegramBotWithCore(&core.BotCore{
+		Messenger: &telegramtest.Recorder{},
+		Config: &config.Config{
+			AdminID:       999999,
+			WebhookURL:    "https://bot.example.com/tg/hook",
+			WebhookListen: "127.0.0.1:0",
+			WebhookPath:   "/hook",
+			WebhookSecret: "s3cret",
+		},
+		Storage: storageMock,
+	}, api)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- telegramBot.Start(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(client.callsTo("setWebhook")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("setWebhook was not called")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	call := client.callsTo("setWebhook")[0]
+	if call["url"] != "https://bot.example.com/tg/hook" || call["secret_token"] != "s3cret" {
+		t.Errorf("Unexpected setWebhook params %v", call)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after cancellation")
+	}
+	if len(client.callsTo("deleteWebhook")) != 1 {
+		t.Errorf("Expected deleteWebhook on stop")
+	}
+}
+
+func TestPollingResumesFromSavedOffset(t *testing.T) {
+	client := &stubClient{updates: []string{"[" + strings.Replace(startUpdate, `"update_id": 1`, `"update_id": 42`, 1) + "]"}}
+	api, err := tgbotapi.NewBotAPIWithClient("fake_token", tgbotapi.APIEndpoint, client)
+	if err != nil {
+		t.Fatalf("NewBotAPIWithClient failed: %v", err)
+	}
+	storageMock := new(MockStorage)
+	storageMock.On("GetLastUpdateID").Return(41, nil)
+	storageMock.On("GetState", mock.Anything).Return("", nil)
+	storageMock.On("SetState", mock.Anything, mock.Anything).Return(nil)
+	storageMock.On("SetLastUpdateID", 42).Return(nil)
+
+	tg := &telegramtest.Recorder{}
+	telegramBot := bot.NewTelegramBotWithCore(&core.BotCore{
+		Messenger: tg,
+		Config:    &config.Config{AdminID: 999999},
+		Storage:   storageMock,
+	}, api)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- telegramBot.Start(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(tg.Messages(12345)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Update was not processed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after cancellation")
+	}
+	telegramBot.Shutdown(time.Second)
+
+	if call := client.callsTo("getUpdates")[0]; call["offset"] != "42" {
+		t.Errorf("Expected polling to resume from offset 42, got %v", call)
+	}
+	if len(client.callsTo("deleteWebhook")) != 1 {
+		t.Errorf("Expected leftover webhook to be removed before polling")
+	}
+	storageMock.AssertCalled(t, "SetLastUpdateID", 42)
+}
+
+func TestOffsetWaitsForUnfinishedUpdates(t *testing.T) {
+	release := make(chan struct{})
+	tg := &telegramtest.Recorder{Hook: func(c telegramtest.Call) error {
+		if c.ChatID == 100 {
+			<-release
+		}
+		return nil
+	}}
+	storageMock := new(MockStorage)
+	storageMock.On("SetLastUpdateID", 2).Return(nil)
+	storageMock.On("Ping").Return(nil)
+
+	telegramBot := bot.NewTelegramBotWithCore(&core.BotCore{
+		Messenger: tg,
+		Config:    &config.Config{AdminID: 999999, Workers: 2, WorkerQueueSize: 4, ReadyUpdateStall: 50 * time.Millisecond},
+		Storage:   storageMock,
+	}, nil)
+	handler := telegramBot.WebhookHandler("")
+	// post отправляет обновление и возвращает канал с кодом ответа webhook
+	post := func(id, chat int) <-chan int {
+		body := strings.NewReplacer(`"update_id": 1`, fmt.Sprintf(`"update_id": %d`, id),
+			"12345", strconv.Itoa(chat)).Replace(startUpdate)
+		status := make(chan int, 1)
+		go func() {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+			status <- rec.Code
+		}()
+		return status
+	}
+
+	// Первое обновление «зависло» в чате 100, второе из чата 201 обрабатывается параллельно
+	first := post(1, 100)
+	for telegramBot.WorkerStats().InFlight == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if status := <-post(2, 201); status != http.StatusOK {
+		t.Errorf("Expected the second update to be acknowledged, got %d", status)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(tg.Messages(201)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Update of another chat was blocked by the slow one")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	storageMock.AssertNotCalled(t, "SetLastUpdateID", 2)
+
+	// Пока первое обновление висит дольше READY_UPDATE_STALL, бот не готов
+	time.Sleep(100 * time.Millisecond)
+	if c := telegramBot.Health().Run(context.Background()).Components["updates"]; c.Status != health.StatusFail {
+		t.Errorf("Expected stalled updates to fail readiness, got %+v", c)
+	}
+
+	// Повторная доставка зависшего обновления ждёт ту же обработку, а не запускает новую
+	again := post(1, 100)
+	select {
+	case status := <-first:
+		t.Fatalf("Expected the webhook to wait for the update to be handled, got %d", status)
+	case status := <-again:
+		t.Fatalf("Expected the redelivery to wait for the update to be handled, got %d", status)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if status, again := <-first, <-again; status != http.StatusOK || again != http.StatusOK {
+		t.Errorf("Expected both deliveries to be acknowledged after handling, got %d and %d", status, again)
+	}
+	telegramBot.Shutdown(time.Second)
+	if report := telegramBot.Health().Run(context.Background()); report.Status != health.StatusOK {
+		t.Errorf("Expected readiness after the queue drained, got %+v", report)
+	}
+	storageMock.AssertCalled(t, "SetLastUpdateID", 2)
+	storageMock.AssertNotCalled(t, "SetLastUpdateID", 1)
+	if stats := telegramBot.WorkerStats(); stats.Processed != 2 || stats.Workers != 2 {
+		t.Errorf("Unexpected worker stats: %+v", stats)
+	}
+}
+
+func TestShutdownWithFullQueue(t *testing.T) {
+	release := make(chan struct{})
+	tg := &telegramtest.Recorder{Hook: func(c telegramtest.Call) error {
+		<-release
+		return nil
+	}}
+	storageMock := new(MockStorage)
+	storageMock.On("SetLastUpdateID", mock.Anything).Return(nil)
+	telegramBot := bot.NewTelegramBotWithCore(&core.BotCore{
+		Messenger: tg,
+		Config:    &config.Config{AdminID: 999999, Workers: 1, WorkerQueueSize: 1},
+		Storage:   storageMock,
+	}, nil)
+	handler := telegramBot.WebhookHandler("")
+	post := func(id int) <-chan int {
+		body := strings.Replace(startUpdate, `"update_id": 1`, fmt.Sprintf(`"update_id": %d`, id), 1)
+		status := make(chan int, 1)
+		go func() {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+			status <- rec.Code
+		}()
+		return status
+	}
+	waitFor := func(cond func(dispatch.Stats) bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond(telegramBot.WorkerStats()) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out, worker stats: %+v", telegramBot.WorkerStats())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Обработчик завис на первом обновлении, второе заняло очередь, третье ждёт места
+	post(1)
+	waitFor(func(s dispatch.Stats) bool { return s.InFlight == 1 })
+	post(2)
+	waitFor(func(s dispatch.Stats) bool { return s.Queued == 1 })
+	third := post(3)
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	telegramBot.Shutdown(100 * time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Shutdown to give up after its timeout, took %s", elapsed)
+	}
+	if status := <-third; status != http.StatusServiceUnavailable {
+		t.Errorf("Expected the update without a place in the queue to be refused, got %d", status)
+	}
+	close(release)
+}
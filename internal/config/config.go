@@ -1,18 +1,132 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	TelegramBotToken string
-	AdminID          int
-	DatabaseURL      string
-	CohereKey        string
-	ProxyURL         string
+	// TelegramAPIEndpoint — шаблон адреса Bot API (https://host/bot%s/%s): локальный
+	// telegram-bot-api или тестовый сервер. Пусто — api.telegram.org.
+	TelegramAPIEndpoint string
+	AdminID             int
+	// AdminIDs — дополнительные администраторы (ADMIN_IDS через запятую) помимо AdminID.
+	AdminIDs    []int
+	DatabaseURL string
+	CohereKey   string
+	ProxyURL    string
+	// SuggestExamples — сколько последних отвеченных вопросов передавать нейросети
+	// как примеры стиля для /suggest (0 — не передавать).
+	SuggestExamples int
+
+	// Настройки нейросети для /ask и /suggest
+	LLMProvider    string // cohere | openai | local | fake
+	LLMAPIKey      string
+	LLMBaseURL     string
+	LLMModel       string
+	LLMMaxTokens   int
+	LLMTemperature float64
+	LLMTimeout     time.Duration
+
+	// Диалоги /ask: сколько реплик читать из базы, бюджет токенов истории
+	// и время неактивности, после которого диалог начинается заново (0 — без ограничения).
+	ChatHistoryLimit  int
+	ChatHistoryTokens int
+	ChatSessionTTL    time.Duration
+	// StreamEditInterval — минимальный интервал между правками сообщения при потоковом ответе
+	// (Telegram ограничивает частоту редактирования).
+	StreamEditInterval time.Duration
+
+	// ClassifyQuestions включает фоновую классификацию новых вопросов нейросетью.
+	ClassifyQuestions bool
+	// FilterRulesFile — YAML-файл правил локального фильтра (пусто — фильтр выключен).
+	FilterRulesFile string
+	// CrisisRulesFile — YAML-файл кризисных ключевых слов и сообщений (пусто — выключено).
+	CrisisRulesFile string
+
+	// DigestTime — время ежедневного дайджеста неотвеченных вопросов администраторам
+	// в формате ЧЧ:ММ (пусто — выключено).
+	DigestTime string
+	// DigestChunkChars — максимальная длина текста вопросов в одном запросе к нейросети при пересказе.
+	DigestChunkChars int
+
+	// TranslateTo — язык администраторов (ru, uk, en): вопросы переводятся на него,
+	// а ответы — обратно на язык автора (пусто — перевод выключен).
+	TranslateTo string
+	// Translator — чем переводить: llm (настроенная нейросеть) или fake.
+	Translator string
+
+	// DefaultLanguage — язык бота для пользователей, чей язык не поддерживается (ru, uk, en).
+	DefaultLanguage string
+
+	// Режим webhook включается, если задан WebhookURL — публичный адрес, на который Telegram
+	// отправляет обновления. WebhookListen и WebhookPath — где их принимает встроенный сервер
+	// (за обратным прокси путь может отличаться от пути в URL). WebhookSecret проверяется
+	// в заголовке X-Telegram-Bot-Api-Secret-Token.
+	WebhookURL    string
+	WebhookListen string
+	WebhookPath   string
+	WebhookSecret string
+	// WebhookCertFile и WebhookKeyFile включают HTTPS на встроенном сервере;
+	// WebhookUploadCert — отправить сертификат в Telegram (для самоподписанного).
+	WebhookCertFile   string
+	WebhookKeyFile    string
+	WebhookUploadCert bool
+
+	// ShutdownTimeout — сколько при остановке ждать завершения начатой обработки.
+	ShutdownTimeout time.Duration
+
+	// Workers — сколько обновлений обрабатывать параллельно (обновления одного чата — всегда
+	// по порядку); WorkerQueueSize — длина очереди каждого обработчика, при заполнении
+	// приём новых обновлений приостанавливается.
+	Workers         int
+	WorkerQueueSize int
+
+	// RateLimit — сколько команд и вопросов в минуту может отправить пользователь
+	// (0 — без ограничения), RateLimitBurst — сколько подряд. На администраторов не действует.
+	RateLimit      int
+	RateLimitBurst int
+
+	// UpdatesLog — файл JSONL, в который дописываются все входящие обновления (пусто — не писать);
+	// UpdatesLogRedact — скрывать в нём личные данные и тексты, кроме команд.
+	UpdatesLog       string
+	UpdatesLogRedact bool
+
+	// LogLevel — минимальный уровень журнала (debug, info, warn, error), LogFormat — text или json.
+	// LogIDSalt — ключ хеширования ID пользователей и чатов в журнале; пустой — новый при каждом
+	// запуске, и строки одного пользователя нельзя связать между перезапусками.
+	LogLevel  string
+	LogFormat string
+	LogIDSalt string
+
+	// StatusListen — адрес служебного HTTP-сервера: метрики Prometheus (/metrics), живость
+	// (/healthz) и готовность (/readyz); пусто — выключен.
+	StatusListen string
+	// TelegramCheckEvery — как часто проверять Telegram запросом getMe (0 — не проверять);
+	// ReadyTelegramMaxAge — сколько getMe может не проходить, прежде чем /readyz откажет;
+	// ReadyUpdateStall — сколько принятые обновления могут ждать без единого обработанного.
+	TelegramCheckEvery  time.Duration
+	ReadyTelegramMaxAge time.Duration
+	ReadyUpdateStall    time.Duration
+
+	// WebListen — адрес веб-панели администраторов (пусто — выключена); WebURL — её публичный
+	// адрес для ссылок входа и Telegram Login Widget (пусто — http://<WebListen>).
+	// WebSessionTTL — сколько действует вход в панель, WebLoginTTL — одноразовая ссылка
+	// входа из /web и данные Login Widget.
+	WebListen     string
+	WebURL        string
+	WebSessionTTL time.Duration
+	WebLoginTTL   time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -20,18 +134,165 @@ func LoadConfig() (*Config, error) {
 	viper.SetConfigType("env")
 	viper.AddConfigPath(".")
 	viper.AutomaticEnv()
+	viper.SetDefault("SUGGEST_EXAMPLES", 3)
+	viper.SetDefault("LLM_PROVIDER", "cohere")
+	viper.SetDefault("LLM_MAX_TOKENS", 512)
+	viper.SetDefault("LLM_TEMPERATURE", 0.7)
+	viper.SetDefault("LLM_TIMEOUT", "60s")
+	viper.SetDefault("CHAT_HISTORY_LIMIT", 40)
+	viper.SetDefault("CHAT_HISTORY_TOKENS", 2000)
+	viper.SetDefault("STREAM_EDIT_INTERVAL", "1500ms")
+	viper.SetDefault("DIGEST_CHUNK_CHARS", 6000)
+	viper.SetDefault("TRANSLATOR", "llm")
+	viper.SetDefault("DEFAULT_LANGUAGE", "ru")
+	viper.SetDefault("WEBHOOK_LISTEN", ":8080")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", "10s")
+	viper.SetDefault("WORKERS", 8)
+	viper.SetDefault("WORKER_QUEUE_SIZE", 64)
+	viper.SetDefault("RATE_LIMIT", 20)
+	viper.SetDefault("RATE_LIMIT_BURST", 5)
+	viper.SetDefault("UPDATES_LOG_REDACT", true)
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_FORMAT", "text")
+	viper.SetDefault("TELEGRAM_CHECK_INTERVAL", "30s")
+	viper.SetDefault("READY_TELEGRAM_MAX_AGE", "2m")
+	viper.SetDefault("READY_UPDATE_STALL", "2m")
+	viper.SetDefault("WEB_SESSION_TTL", "12h")
+	viper.SetDefault("WEB_LOGIN_TTL", "10m")
 
 	if err := viper.ReadInConfig(); err != nil {
 		log.Println("Config file not found, using environment variables")
 	}
 
 	config := &Config{
-		TelegramBotToken: viper.GetString("TELEGRAM_BOT_TOKEN"),
-		AdminID:          viper.GetInt("ADMIN_ID"),
-		DatabaseURL:      viper.GetString("DATABASE_URL"),
-		CohereKey:        os.Getenv("COHERE_API_KEY"),
-		ProxyURL:         viper.GetString("PROXY_URL"),
+		TelegramBotToken:    viper.GetString("TELEGRAM_BOT_TOKEN"),
+		TelegramAPIEndpoint: viper.GetString("TELEGRAM_API_ENDPOINT"),
+		AdminID:             viper.GetInt("ADMIN_ID"),
+		DatabaseURL:         viper.GetString("DATABASE_URL"),
+		CohereKey:           os.Getenv("COHERE_API_KEY"),
+		ProxyURL:            viper.GetString("PROXY_URL"),
+		SuggestExamples:     viper.GetInt("SUGGEST_EXAMPLES"),
+		LLMProvider:         viper.GetString("LLM_PROVIDER"),
+		LLMAPIKey:           viper.GetString("LLM_API_KEY"),
+		LLMBaseURL:          viper.GetString("LLM_BASE_URL"),
+		LLMModel:            viper.GetString("LLM_MODEL"),
+		LLMMaxTokens:        viper.GetInt("LLM_MAX_TOKENS"),
+		LLMTemperature:      viper.GetFloat64("LLM_TEMPERATURE"),
+		LLMTimeout:          viper.GetDuration("LLM_TIMEOUT"),
+		ChatHistoryLimit:    viper.GetInt("CHAT_HISTORY_LIMIT"),
+		ChatHistoryTokens:   viper.GetInt("CHAT_HISTORY_TOKENS"),
+		ChatSessionTTL:      viper.GetDuration("CHAT_SESSION_TTL"),
+		StreamEditInterval:  viper.GetDuration("STREAM_EDIT_INTERVAL"),
+		ClassifyQuestions:   viper.GetBool("CLASSIFY_QUESTIONS"),
+		FilterRulesFile:     viper.GetString("FILTER_RULES_FILE"),
+		CrisisRulesFile:     viper.GetString("CRISIS_RULES_FILE"),
+		DigestTime:          viper.GetString("DIGEST_TIME"),
+		DigestChunkChars:    viper.GetInt("DIGEST_CHUNK_CHARS"),
+		TranslateTo:         strings.ToLower(viper.GetString("TRANSLATE_TO")),
+		Translator:          viper.GetString("TRANSLATOR"),
+		DefaultLanguage:     strings.ToLower(viper.GetString("DEFAULT_LANGUAGE")),
+		WebhookURL:          viper.GetString("WEBHOOK_URL"),
+		WebhookListen:       viper.GetString("WEBHOOK_LISTEN"),
+		WebhookPath:         viper.GetString("WEBHOOK_PATH"),
+		WebhookSecret:       viper.GetString("WEBHOOK_SECRET"),
+		WebhookCertFile:     viper.GetString("WEBHOOK_CERT_FILE"),
+		WebhookKeyFile:      viper.GetString("WEBHOOK_KEY_FILE"),
+		WebhookUploadCert:   viper.GetBool("WEBHOOK_UPLOAD_CERT"),
+		ShutdownTimeout:     viper.GetDuration("SHUTDOWN_TIMEOUT"),
+		Workers:             viper.GetInt("WORKERS"),
+		WorkerQueueSize:     viper.GetInt("WORKER_QUEUE_SIZE"),
+		RateLimit:           viper.GetInt("RATE_LIMIT"),
+		RateLimitBurst:      viper.GetInt("RATE_LIMIT_BURST"),
+		UpdatesLog:          viper.GetString("UPDATES_LOG"),
+		UpdatesLogRedact:    viper.GetBool("UPDATES_LOG_REDACT"),
+		LogLevel:            viper.GetString("LOG_LEVEL"),
+		LogFormat:           viper.GetString("LOG_FORMAT"),
+		LogIDSalt:           viper.GetString("LOG_ID_SALT"),
+		StatusListen:        viper.GetString("STATUS_LISTEN"),
+		TelegramCheckEvery:  viper.GetDuration("TELEGRAM_CHECK_INTERVAL"),
+		ReadyTelegramMaxAge: viper.GetDuration("READY_TELEGRAM_MAX_AGE"),
+		ReadyUpdateStall:    viper.GetDuration("READY_UPDATE_STALL"),
+		WebListen:           viper.GetString("WEB_LISTEN"),
+		WebURL:              strings.TrimRight(viper.GetString("WEB_URL"), "/"),
+		WebSessionTTL:       viper.GetDuration("WEB_SESSION_TTL"),
+		WebLoginTTL:         viper.GetDuration("WEB_LOGIN_TTL"),
+	}
+
+	for _, field := range strings.Split(viper.GetString("ADMIN_IDS"), ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		id, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный ADMIN_IDS: %w", err)
+		}
+		config.AdminIDs = append(config.AdminIDs, id)
+	}
+
+	// Совместимость со старыми .env: ключ Cohere задавался через COHERE_API_KEY
+	if config.LLMAPIKey == "" && config.LLMProvider == "cohere" {
+		config.LLMAPIKey = config.CohereKey
+	}
+
+	if config.DigestTime != "" {
+		if _, err := time.Parse("15:04", config.DigestTime); err != nil {
+			return nil, fmt.Errorf("некорректный DIGEST_TIME %q, ожидается ЧЧ:ММ", config.DigestTime)
+		}
+	}
+
+	if err := config.validateWebhook(); err != nil {
+		return nil, err
+	}
+	if err := config.validateWeb(); err != nil {
+		return nil, err
 	}
 
 	return config, nil
 }
+
+// secretTokenPattern — допустимые символы секрета webhook по документации Bot API.
+var secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// validateWebhook проверяет настройки webhook и выводит путь из WEBHOOK_URL, если WEBHOOK_PATH не задан.
+func (c *Config) validateWebhook() error {
+	if c.WebhookURL == "" {
+		return nil
+	}
+	u, err := url.Parse(c.WebhookURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("некорректный WEBHOOK_URL %q", c.WebhookURL)
+	}
+	if c.WebhookPath == "" {
+		c.WebhookPath = u.Path
+	}
+	if c.WebhookSecret != "" && !secretTokenPattern.MatchString(c.WebhookSecret) {
+		return fmt.Errorf("WEBHOOK_SECRET может содержать только A-Z, a-z, 0-9, _ и - (до 256 символов)")
+	}
+	if (c.WebhookCertFile == "") != (c.WebhookKeyFile == "") {
+		return fmt.Errorf("для HTTPS нужны оба параметра: WEBHOOK_CERT_FILE и WEBHOOK_KEY_FILE")
+	}
+	return nil
+}
+
+// validateWeb проверяет WEB_URL и выводит его из WEB_LISTEN, если он не задан.
+func (c *Config) validateWeb() error {
+	if c.WebListen == "" {
+		return nil
+	}
+	if c.WebURL == "" {
+		host, port, err := net.SplitHostPort(c.WebListen)
+		if err != nil {
+			return fmt.Errorf("некорректный WEB_LISTEN %q: %w", c.WebListen, err)
+		}
+		if host == "" {
+			host = "localhost"
+		}
+		c.WebURL = "http://" + net.JoinHostPort(host, port)
+		return nil
+	}
+	u, err := url.Parse(c.WebURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("некорректный WEB_URL %q", c.WebURL)
+	}
+	return nil
+}
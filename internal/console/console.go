@@ -0,0 +1,295 @@
+// Package console — консольный транспорт для локальной разработки: строки из stdin становятся
+// сообщениями вымышленного пользователя, проходят через те же хендлеры, что и обновления
+// Telegram, а ответы бота печатаются в терминал. Токен и аккаунт Telegram не нужны.
+package console
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Handler — то, что консоль вызывает для каждого обновления (TelegramBot.HandleUpdate).
+type Handler interface {
+	HandleUpdate(update tgbotapi.Update)
+}
+
+// Messenger печатает всё, что бот отправляет, и запоминает inline-кнопки для :press.
+// Реализует core.Messenger.
+type Messenger struct {
+	mu        sync.Mutex
+	out       io.Writer
+	messageID int
+	// buttons — последние inline-кнопки в каждом чате и сообщение, под которым они показаны.
+	buttons map[int64]keyboard
+}
+
+type keyboard struct {
+	messageID int
+	buttons   []tgbotapi.InlineKeyboardButton
+}
+
+// NewMessenger возвращает Messenger, пишущий в out.
+func NewMessenger(out io.Writer) *Messenger {
+	return &Messenger{out: out, buttons: make(map[int64]keyboard)}
+}
+
+// Send печатает сообщение, медиа или правку.
+func (m *Messenger) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messageID++
+	msg := tgbotapi.Message{MessageID: m.messageID, Chat: &tgbotapi.Chat{}}
+	switch c := c.(type) {
+	case tgbotapi.MessageConfig:
+		msg.Chat.ID, msg.Text = c.ChatID, c.Text
+		m.printf(c.ChatID, "%s", c.Text)
+		if markup, ok := c.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup); ok {
+			m.keyboard(c.ChatID, msg.MessageID, &markup)
+		}
+	case tgbotapi.PhotoConfig:
+		msg.Chat.ID = c.ChatID
+		m.printf(c.ChatID, "🖼 %s %s", fileName(c.File), c.Caption)
+	case tgbotapi.VideoConfig:
+		msg.Chat.ID = c.ChatID
+		m.printf(c.ChatID, "🎞 %s %s", fileName(c.File), c.Caption)
+	case tgbotapi.EditMessageTextConfig:
+		msg.MessageID, msg.Chat.ID, msg.Text = c.MessageID, c.ChatID, c.Text
+		m.printf(c.ChatID, "✎ #%d: %s", c.MessageID, c.Text)
+		m.keyboard(c.ChatID, c.MessageID, c.ReplyMarkup)
+	default:
+		return m.request(c)
+	}
+	return msg, nil
+}
+
+// Request печатает служебный запрос, если он заметен пользователю.
+func (m *Messenger) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.request(c); err != nil {
+		return nil, err
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (m *Messenger) request(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	switch c := c.(type) {
+	case tgbotapi.CallbackConfig:
+		if c.Text != "" {
+			fmt.Fprintf(m.out, "  💬 %s\n", c.Text)
+		}
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		m.keyboard(c.ChatID, c.MessageID, c.ReplyMarkup)
+	case tgbotapi.ChatActionConfig, tgbotapi.SetMyCommandsConfig, tgbotapi.DeleteMyCommandsConfig:
+	default:
+		fmt.Fprintf(m.out, "  [%T]\n", c)
+	}
+	return tgbotapi.Message{}, nil
+}
+
+// keyboard печатает inline-кнопки с номерами для :press; пустая разметка убирает кнопки.
+func (m *Messenger) keyboard(chatID int64, messageID int, markup *tgbotapi.InlineKeyboardMarkup) {
+	if markup == nil {
+		return
+	}
+	var buttons []tgbotapi.InlineKeyboardButton
+	for _, row := range markup.InlineKeyboard {
+		buttons = append(buttons, row...)
+	}
+	if len(buttons) == 0 {
+		delete(m.buttons, chatID)
+		return
+	}
+	m.buttons[chatID] = keyboard{messageID: messageID, buttons: buttons}
+	for i, b := range buttons {
+		fmt.Fprintf(m.out, "  [%d] %s\n", i+1, b.Text)
+	}
+}
+
+// button возвращает кнопку n (с единицы) из последней клавиатуры чата.
+func (m *Messenger) button(chatID int64, n int) (tgbotapi.InlineKeyboardButton, int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kb := m.buttons[chatID]
+	if n < 1 || n > len(kb.buttons) || kb.buttons[n-1].CallbackData == nil {
+		return tgbotapi.InlineKeyboardButton{}, 0, false
+	}
+	return kb.buttons[n-1], kb.messageID, true
+}
+
+func (m *Messenger) printf(chatID int64, format string, args ...any) {
+	fmt.Fprintf(m.out, "🤖 → %d: %s\n", chatID, strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+func fileName(file tgbotapi.RequestFileData) string {
+	switch f := file.(type) {
+	case tgbotapi.FileID:
+		return string(f)
+	case tgbotapi.FilePath:
+		return string(f)
+	}
+	return ""
+}
+
+// Console читает строки и превращает их в обновления от имени текущего пользователя.
+type Console struct {
+	bot       Handler
+	messenger *Messenger
+	out       io.Writer
+	// userID — пользователь по умолчанию (:as user), adminID — администратор (:as admin).
+	userID, adminID int64
+
+	current  int64
+	updateID int
+}
+
+// New возвращает консоль: сообщения от имени userID передаются bot, ответы печатает messenger.
+func New(bot Handler, messenger *Messenger, out io.Writer, userID, adminID int64) *Console {
+	return &Console{bot: bot, messenger: messenger, out: out, userID: userID, adminID: adminID, current: userID}
+}
+
+const help = `Строки отправляются боту как сообщения. Служебные команды:
+  :as admin | :as user | :as <id>  — сменить отправителя
+  :photo <путь> [подпись]          — отправить фото
+  :video <путь> [подпись]          — отправить видео
+  :press <n>                       — нажать кнопку n под последним сообщением
+  :help                            — эта справка
+  :quit                            — выход`
+
+// Run читает строки из in, пока они не кончатся, не придёт :quit или не отменён ctx.
+func (c *Console) Run(ctx context.Context, in io.Reader) error {
+	lines := make(chan string)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-done:
+				return
+			}
+		}
+		errs <- scanner.Err()
+	}()
+
+	fmt.Fprintln(c.out, help)
+	c.prompt()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case line := <-lines:
+			if strings.TrimSpace(line) == ":quit" {
+				return nil
+			}
+			c.Handle(line)
+			c.prompt()
+		}
+	}
+}
+
+// Handle обрабатывает одну строку: служебную команду консоли или сообщение боту.
+func (c *Console) Handle(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	if !strings.HasPrefix(line, ":") {
+		c.send(&tgbotapi.Message{Text: line})
+		return
+	}
+
+	cmd, arg, _ := strings.Cut(line[1:], " ")
+	arg = strings.TrimSpace(arg)
+	switch cmd {
+	case "as":
+		c.switchUser(arg)
+	case "photo", "video":
+		path, caption, _ := strings.Cut(arg, " ")
+		if path == "" {
+			fmt.Fprintf(c.out, "Использование: :%s <путь> [подпись]\n", cmd)
+			return
+		}
+		msg := &tgbotapi.Message{Caption: strings.TrimSpace(caption)}
+		if cmd == "photo" {
+			msg.Photo = []tgbotapi.PhotoSize{{FileID: path, FileUniqueID: filepath.Base(path)}}
+		} else {
+			msg.Video = &tgbotapi.Video{FileID: path, FileUniqueID: filepath.Base(path)}
+		}
+		c.send(msg)
+	case "press":
+		n, _ := strconv.Atoi(arg)
+		c.press(n)
+	case "help":
+		fmt.Fprintln(c.out, help)
+	default:
+		fmt.Fprintf(c.out, "Неизвестная команда консоли :%s, см. :help\n", cmd)
+	}
+}
+
+func (c *Console) switchUser(arg string) {
+	switch arg {
+	case "admin":
+		c.current = c.adminID
+	case "user", "":
+		c.current = c.userID
+	default:
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			fmt.Fprintf(c.out, "Некорректный пользователь %q: admin, user или числовой ID\n", arg)
+			return
+		}
+		c.current = id
+	}
+	fmt.Fprintf(c.out, "Теперь вы — %d\n", c.current)
+}
+
+// send дополняет сообщение отправителем, чатом и разметкой команды и передаёт боту.
+func (c *Console) send(msg *tgbotapi.Message) {
+	c.updateID++
+	msg.MessageID = c.updateID
+	msg.From = &tgbotapi.User{ID: c.current, FirstName: "Console"}
+	msg.Chat = &tgbotapi.Chat{ID: c.current, Type: "private"}
+	msg.Date = int(time.Now().Unix())
+	if strings.HasPrefix(msg.Text, "/") {
+		command, _, _ := strings.Cut(msg.Text, " ")
+		msg.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len([]rune(command))}}
+	}
+	c.bot.HandleUpdate(tgbotapi.Update{UpdateID: c.updateID, Message: msg})
+}
+
+// press нажимает inline-кнопку n под последним сообщением с кнопками в чате текущего пользователя.
+func (c *Console) press(n int) {
+	button, messageID, ok := c.messenger.button(c.current, n)
+	if !ok {
+		fmt.Fprintf(c.out, "Нет кнопки %d в чате %d\n", n, c.current)
+		return
+	}
+	c.updateID++
+	c.bot.HandleUpdate(tgbotapi.Update{UpdateID: c.updateID, CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      strconv.Itoa(c.updateID),
+		From:    &tgbotapi.User{ID: c.current, FirstName: "Console"},
+		Message: &tgbotapi.Message{MessageID: messageID, Chat: &tgbotapi.Chat{ID: c.current, Type: "private"}},
+		Data:    *button.CallbackData,
+	}})
+}
+
+func (c *Console) prompt() {
+	fmt.Fprintf(c.out, "%d> ", c.current)
+}
@@ -0,0 +1,71 @@
+package console_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/console"
+)
+
+// echoBot отвечает на каждое сообщение его текстом с кнопкой и запоминает обновления.
+type echoBot struct {
+	messenger *console.Messenger
+	updates   []tgbotapi.Update
+}
+
+func (b *echoBot) HandleUpdate(update tgbotapi.Update) {
+	b.updates = append(b.updates, update)
+	if update.Message == nil {
+		return
+	}
+	reply := tgbotapi.NewMessage(update.Message.Chat.ID, "эхо: "+update.Message.Text)
+	reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Ещё", "again"),
+	))
+	b.messenger.Send(reply)
+}
+
+func TestConsole(t *testing.T) {
+	var out strings.Builder
+	messenger := console.NewMessenger(&out)
+	bot := &echoBot{messenger: messenger}
+	c := console.New(bot, messenger, &out, 1000, 999)
+
+	input := strings.Join([]string{
+		"/start",
+		":as admin",
+		":photo /tmp/cat.jpg кот",
+		":press 1",
+		":as 42",
+		":press 1",
+		":quit",
+		"не дойдёт",
+	}, "\n")
+	if err := c.Run(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(bot.updates) != 3 {
+		t.Fatalf("Expected 3 updates, got %d:\n%s", len(bot.updates), out.String())
+	}
+	start := bot.updates[0].Message
+	if start.From.ID != 1000 || start.Chat.ID != 1000 || start.Command() != "start" {
+		t.Errorf("Expected /start command from the default user, got %+v", start)
+	}
+	photo := bot.updates[1].Message
+	if photo.From.ID != 999 || len(photo.Photo) != 1 || photo.Photo[0].FileID != "/tmp/cat.jpg" || photo.Caption != "кот" {
+		t.Errorf("Expected photo from the admin, got %+v", photo)
+	}
+	cb := bot.updates[2].CallbackQuery
+	if cb == nil || cb.From.ID != 999 || cb.Data != "again" || cb.Message.Chat.ID != 999 {
+		t.Errorf("Expected button press by the admin, got %+v", bot.updates[2])
+	}
+
+	for _, want := range []string{"🤖 → 1000: эхо: /start", "  [1] Ещё", "Теперь вы — 999", "Нет кнопки 1 в чате 42"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
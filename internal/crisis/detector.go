@@ -0,0 +1,94 @@
+// Package crisis — распознавание сообщений о самоповреждении, насилии и других кризисных ситуациях.
+// Ключевые слова и ответы с контактами помощи задаются по языкам в YAML-файле.
+package crisis
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Locale — ключевые слова и сообщения для одного языка.
+type Locale struct {
+	Keywords []string `yaml:"keywords"`
+	// Message отправляется автору вопроса сразу после получения (контакты служб помощи).
+	Message string `yaml:"message"`
+}
+
+// Config — содержимое файла правил.
+type Config struct {
+	// DefaultLanguage используется, если для языка пользователя нет сообщения.
+	DefaultLanguage string            `yaml:"default_language"`
+	Locales         map[string]Locale `yaml:"locales"`
+}
+
+// Match — результат срабатывания детектора.
+type Match struct {
+	Keyword string
+	// Message — сообщение с ресурсами помощи на языке пользователя.
+	Message string
+}
+
+// Detector проверяет текст по ключевым словам всех языков:
+// пользователь может писать не на том языке, что выбран в его клиенте.
+type Detector struct {
+	cfg Config
+}
+
+// Load читает правила из файла. Пустой путь — детектор выключен (nil, nil).
+func Load(path string) (*Detector, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения кризисных правил: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("ошибка разбора кризисных правил: %w", err)
+	}
+	return New(cfg)
+}
+
+// New создаёт детектор из готовой конфигурации.
+func New(cfg Config) (*Detector, error) {
+	if len(cfg.Locales) == 0 {
+		return nil, fmt.Errorf("кризисные правила: не задан ни один язык")
+	}
+	if cfg.DefaultLanguage == "" {
+		cfg.DefaultLanguage = "ru"
+	}
+	if cfg.Locales[cfg.DefaultLanguage].Message == "" {
+		return nil, fmt.Errorf("кризисные правила: нет сообщения для языка по умолчанию %q", cfg.DefaultLanguage)
+	}
+	return &Detector{cfg: cfg}, nil
+}
+
+// Detect ищет в тексте кризисные ключевые слова. languageCode — код языка клиента
+// Telegram (например "uk" или "en-US"), по нему выбирается сообщение для ответа.
+func (d *Detector) Detect(text, languageCode string) (Match, bool) {
+	lower := strings.ToLower(text)
+	for _, loc := range d.cfg.Locales {
+		for _, kw := range loc.Keywords {
+			if kw != "" && strings.Contains(lower, strings.ToLower(kw)) {
+				return Match{Keyword: kw, Message: d.message(languageCode)}, true
+			}
+		}
+	}
+	return Match{}, false
+}
+
+func (d *Detector) message(languageCode string) string {
+	lang := strings.ToLower(languageCode)
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	if loc, ok := d.cfg.Locales[lang]; ok && loc.Message != "" {
+		return loc.Message
+	}
+	return d.cfg.Locales[d.cfg.DefaultLanguage].Message
+}
@@ -0,0 +1,75 @@
+package crisis_test
+
+import (
+	"testing"
+
+	"telegram-anonymous-bot/internal/crisis"
+)
+
+func newDetector(t *testing.T) *crisis.Detector {
+	t.Helper()
+	d, err := crisis.New(crisis.Config{
+		DefaultLanguage: "ru",
+		Locales: map[string]crisis.Locale{
+			"ru": {Keywords: []string{"не хочу жить"}, Message: "ru-help"},
+			"uk": {Keywords: []string{"не хочу жити"}, Message: "uk-help"},
+			"en": {Keywords: []string{"want to die"}, Message: "en-help"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("crisis.New failed: %v", err)
+	}
+	return d
+}
+
+func TestDetect(t *testing.T) {
+	d := newDetector(t)
+
+	tests := []struct {
+		text, lang string
+		matched    bool
+		message    string
+	}{
+		{text: "Я НЕ ХОЧУ ЖИТЬ", lang: "ru", matched: true, message: "ru-help"},
+		// Ключевые слова проверяются по всем языкам, сообщение — на языке клиента
+		{text: "I want to die", lang: "uk", matched: true, message: "uk-help"},
+		{text: "не хочу жити", lang: "en-GB", matched: true, message: "en-help"},
+		{text: "не хочу жить", lang: "de", matched: true, message: "ru-help"},
+		{text: "Когда экзамен?", lang: "ru"},
+	}
+
+	for _, tt := range tests {
+		m, ok := d.Detect(tt.text, tt.lang)
+		if ok != tt.matched || m.Message != tt.message {
+			t.Errorf("Detect(%q, %q) = %+v, %v; expected matched=%v message=%q", tt.text, tt.lang, m, ok, tt.matched, tt.message)
+		}
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	if _, err := crisis.New(crisis.Config{}); err == nil {
+		t.Errorf("Expected error for empty config")
+	}
+	if _, err := crisis.New(crisis.Config{DefaultLanguage: "en", Locales: map[string]crisis.Locale{
+		"ru": {Message: "ru-help"},
+	}}); err == nil {
+		t.Errorf("Expected error when default language has no message")
+	}
+}
+
+func TestLoadEmptyPath(t *testing.T) {
+	d, err := crisis.Load("")
+	if err != nil || d != nil {
+		t.Errorf("Expected disabled detector for empty path, got %v, %v", d, err)
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	d, err := crisis.Load("../../crisis.example.yaml")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := d.Detect("иногда думаю, что не хочу жить", "ru"); !ok {
+		t.Errorf("Expected example rules to detect a Russian crisis phrase")
+	}
+}
@@ -0,0 +1,167 @@
+// Package digest группирует неотвеченные вопросы по темам и готовит их к пересказу нейросетью.
+package digest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/moderation"
+	"telegram-anonymous-bot/internal/translate"
+)
+
+// NoTopic — тема группы вопросов без тем; название группы для администратора даёт
+// ключ digest.no_topic.
+const NoTopic = ""
+
+// Group — неотвеченные вопросы одной темы.
+type Group struct {
+	Topic     string
+	Questions []*models.Question
+}
+
+// IDs возвращает ID вопросов группы.
+func (g Group) IDs() []int {
+	ids := make([]int, 0, len(g.Questions))
+	for _, q := range g.Questions {
+		ids = append(ids, q.ID)
+	}
+	return ids
+}
+
+// GroupUnanswered отбирает открытые вопросы (без ответа и не отклонённые), заданные не раньше
+// since (нулевое время — все), и группирует их по первой теме. Спам и оскорбления собираются
+// в отдельные группы в конце, срочные вопросы — в группу в начале.
+func GroupUnanswered(questions []*models.Question, since time.Time) []Group {
+	byTopic := make(map[string]*Group)
+	var order []string
+
+	for _, q := range questions {
+		if !q.Open() || (!since.IsZero() && q.CreatedAt.Before(since)) {
+			continue
+		}
+
+		topic := NoTopic
+		switch {
+		case q.Category == moderation.CategoryUrgent,
+			q.Category == moderation.CategorySpam,
+			q.Category == moderation.CategoryAbusive:
+			topic = q.Category
+		case len(q.Tags) > 0:
+			topic = q.Tags[0]
+		}
+
+		g, ok := byTopic[topic]
+		if !ok {
+			g = &Group{Topic: topic}
+			byTopic[topic] = g
+			order = append(order, topic)
+		}
+		g.Questions = append(g.Questions, q)
+	}
+
+	groups := make([]Group, 0, len(order))
+	for _, topic := range order {
+		groups = append(groups, *byTopic[topic])
+	}
+
+	// Срочные первыми, затем по убыванию размера; спам и оскорбления в конце
+	sort.SliceStable(groups, func(i, j int) bool {
+		pi, pj := moderation.Priority(groups[i].Topic), moderation.Priority(groups[j].Topic)
+		if pi != pj {
+			return pi < pj
+		}
+		return len(groups[i].Questions) > len(groups[j].Questions)
+	})
+	return groups
+}
+
+// Chunks делит тексты вопросов группы на части не длиннее maxChars символов,
+// чтобы каждая часть помещалась в один запрос к нейросети.
+func Chunks(g Group, maxChars int) []string {
+	var chunks []string
+	var b strings.Builder
+	for _, q := range g.Questions {
+		text := strings.TrimSpace(q.Text)
+		if text == "" {
+			// Вопрос без текста: только тип вложения, например [photo]
+			text = "[" + q.MediaType + "]"
+		}
+		line := "#" + strconv.Itoa(q.ID) + ": " + text + "\n"
+
+		// Слишком длинный вопрос обрезаем, чтобы он поместился в часть целиком
+		if n := utf8.RuneCountInString(line); maxChars > 0 && n > maxChars {
+			line = string([]rune(line)[:maxChars-2]) + "…\n"
+		}
+		if maxChars > 0 && b.Len() > 0 && utf8.RuneCountInString(b.String())+utf8.RuneCountInString(line) > maxChars {
+			chunks = append(chunks, b.String())
+			b.Reset()
+		}
+		b.WriteString(line)
+	}
+	if b.Len() > 0 {
+		chunks = append(chunks, b.String())
+	}
+	return chunks
+}
+
+// Prompt формирует запрос на пересказ одной части вопросов группы на языке lang
+// (код ISO 639-1): сводку читает администратор, вопросы могут быть на других языках.
+func Prompt(topic, chunk, lang string) string {
+	about := "без общей темы"
+	if topic != NoTopic {
+		about = "на тему «" + topic + "»"
+	}
+	return fmt.Sprintf(`Ниже неотвеченные анонимные вопросы %s с их номерами.
+Вопросы без текста помечены типом вложения, например [photo].
+Кратко (1–3 предложения) перескажи, о чём спрашивают, выдели повторяющиеся вопросы.
+Пиши на языке: %s (код %s), даже если вопросы на другом языке.
+Не выдумывай подробностей и не пересказывай каждый вопрос отдельно.
+
+%s`, about, translate.LanguageName(lang), lang, chunk)
+}
+
+// ParseSince разбирает период для /digest: "24h", "3d", "2w" или дату "02.01.2006" / "2006-01-02".
+// Пустая строка — без ограничения (нулевое время).
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{"02.01.2006", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	if n, err := strconv.Atoi(strings.TrimRight(s, "dw")); err == nil && n > 0 {
+		switch {
+		case strings.HasSuffix(s, "d"):
+			return now.AddDate(0, 0, -n), nil
+		case strings.HasSuffix(s, "w"):
+			return now.AddDate(0, 0, -7*n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("не удалось разобрать период %q (примеры: 24h, 3d, 2w, 01.10.2024)", s)
+}
+
+// NextRun возвращает ближайший момент после now, когда наступает время at ("09:00").
+func NextRun(now time.Time, at string) (time.Time, error) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("некорректное время дайджеста %q, ожидается ЧЧ:ММ", at)
+	}
+	next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next, nil
+}
@@ -0,0 +1,110 @@
+package digest_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"telegram-anonymous-bot/internal/digest"
+	"telegram-anonymous-bot/internal/models"
+)
+
+func TestGroupUnanswered(t *testing.T) {
+	now := time.Date(2024, 10, 7, 12, 0, 0, 0, time.UTC)
+	questions := []*models.Question{
+		{ID: 1, Text: "Когда сессия?", Tags: []string{"учёба"}, CreatedAt: now},
+		{ID: 2, Text: "Купите", Category: "spam", CreatedAt: now},
+		{ID: 3, Text: "Где зачётка?", Tags: []string{"учёба"}, CreatedAt: now},
+		{ID: 4, Text: "Уже отвечен", Tags: []string{"учёба"}, Answered: true, CreatedAt: now},
+		{ID: 5, Text: "Помогите", Category: "urgent", CreatedAt: now},
+		{ID: 6, Text: "Привет", CreatedAt: now},
+		{ID: 7, Text: "Старый", CreatedAt: now.AddDate(0, 0, -10)},
+	}
+
+	groups := digest.GroupUnanswered(questions, now.AddDate(0, 0, -7))
+
+	var topics []string
+	for _, g := range groups {
+		topics = append(topics, g.Topic)
+	}
+	if want := []string{"urgent", "учёба", digest.NoTopic, "spam"}; !reflect.DeepEqual(topics, want) {
+		t.Fatalf("Expected topics %v, got %v", want, topics)
+	}
+	if ids := groups[1].IDs(); !reflect.DeepEqual(ids, []int{1, 3}) {
+		t.Errorf("Expected IDs [1 3] in 'учёба', got %v", ids)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	g := digest.Group{Topic: "t"}
+	for i := 1; i <= 10; i++ {
+		g.Questions = append(g.Questions, &models.Question{ID: i, Text: strings.Repeat("я", 20)})
+	}
+
+	chunks := digest.Chunks(g, 60)
+	if len(chunks) != 5 {
+		t.Fatalf("Expected 5 chunks of 2 questions, got %d", len(chunks))
+	}
+	for _, c := range chunks {
+		if n := len([]rune(c)); n > 60 {
+			t.Errorf("Chunk is %d characters long, limit is 60", n)
+		}
+	}
+
+	// Вопрос длиннее лимита обрезается, а не теряется
+	long := digest.Group{Questions: []*models.Question{{ID: 1, Text: strings.Repeat("я", 200)}}}
+	if chunks := digest.Chunks(long, 60); len(chunks) != 1 || len([]rune(chunks[0])) > 60 {
+		t.Errorf("Expected a single truncated chunk, got %v", chunks)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 10, 7, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]time.Time{
+		"":           {},
+		"24h":        now.Add(-24 * time.Hour),
+		"3d":         now.AddDate(0, 0, -3),
+		"2w":         now.AddDate(0, 0, -14),
+		"01.10.2024": time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		"2024-10-01": time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := digest.ParseSince(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseSince(%q) = %v, %v; expected %v", in, got, err, want)
+		}
+	}
+	if _, err := digest.ParseSince("вчера", now); err == nil {
+		t.Errorf("Expected error for unknown period")
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2024, 10, 7, 12, 0, 0, 0, time.UTC)
+
+	next, err := digest.NextRun(now, "09:00")
+	if err != nil || !next.Equal(time.Date(2024, 10, 8, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected tomorrow 09:00, got %v (err=%v)", next, err)
+	}
+	next, _ = digest.NextRun(now, "18:30")
+	if !next.Equal(time.Date(2024, 10, 7, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected today 18:30, got %v", next)
+	}
+	if _, err := digest.NextRun(now, "25:00"); err == nil {
+		t.Errorf("Expected error for invalid time")
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	prompt := digest.Prompt(digest.NoTopic, "#1: Коли сесія?\n#2: [photo]\n", "en")
+	for _, want := range []string{"без общей темы", "английский (код en)", "#2: [photo]"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected %q in prompt:\n%s", want, prompt)
+		}
+	}
+	if prompt := digest.Prompt("учёба", "#1: Когда сессия?\n", "ru"); !strings.Contains(prompt, "на тему «учёба»") {
+		t.Errorf("Expected the topic in prompt:\n%s", prompt)
+	}
+}
@@ -0,0 +1,201 @@
+// Package dispatch — пул обработчиков обновлений с разбиением по чатам.
+// Задачи одного чата всегда попадают к одному обработчику и выполняются по порядку,
+// задачи разных чатов — параллельно.
+package dispatch
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats — снимок метрик пула.
+type Stats struct {
+	Workers   int
+	QueueSize int
+	// Queued — задачи, ожидающие в очередях; InFlight — выполняющиеся сейчас.
+	Queued   int64
+	InFlight int64
+	// Processed — сколько задач выполнено с момента запуска.
+	Processed int64
+	// Blocked — сколько раз Submit ждал освобождения места в заполненной очереди,
+	// BlockedTime — суммарное время ожидания.
+	Blocked     int64
+	BlockedTime time.Duration
+	// Rejected — задачи, не попавшие в очередь: после Close или из-за отмены ctx в Submit.
+	Rejected int64
+}
+
+// Pool — набор обработчиков, у каждого своя ограниченная очередь.
+type Pool struct {
+	shards    []chan func()
+	queueSize int
+	wg        sync.WaitGroup
+
+	// mu защищает closed и очереди от гонки Submit и Close: очереди закрываются только
+	// после того, как все Submit вышли. done закрывается первым и будит Submit, ждущие
+	// места в очереди, чтобы Close не ждал их.
+	mu        sync.RWMutex
+	closed    bool
+	done      chan struct{}
+	closeDone sync.Once
+
+	queued      atomic.Int64
+	inFlight    atomic.Int64
+	processed   atomic.Int64
+	blocked     atomic.Int64
+	blockedTime atomic.Int64
+	rejected    atomic.Int64
+
+	// OnBlocked, если задан, вызывается после того, как Submit ждал место в очереди.
+	OnBlocked func(shard int, waited time.Duration)
+}
+
+// New запускает workers обработчиков с очередями на queueSize задач.
+// Значения меньше 1 заменяются на 1.
+func New(workers, queueSize int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	p := &Pool{
+		shards:    make([]chan func(), workers),
+		queueSize: queueSize,
+		done:      make(chan struct{}),
+	}
+	for i := range p.shards {
+		p.shards[i] = make(chan func(), queueSize)
+		p.wg.Add(1)
+		go p.run(p.shards[i])
+	}
+	return p
+}
+
+// Shard возвращает номер обработчика для ключа (ID чата).
+func (p *Pool) Shard(key int64) int {
+	// Отрицательные ID групп и каналов тоже должны распределяться равномерно
+	return int(uint64(key) % uint64(len(p.shards)))
+}
+
+// Submit ставит задачу в очередь обработчика, отвечающего за key. Если очередь заполнена,
+// Submit ждёт (обратное давление на источник обновлений), пока не отменён ctx и не вызван
+// Close. Задача, не попавшая в очередь, отклоняется, и возвращается false.
+func (p *Pool) Submit(ctx context.Context, key int64, task func()) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed || p.stopping() {
+		p.rejected.Add(1)
+		return false
+	}
+
+	shard := p.Shard(key)
+	p.queued.Add(1)
+	select {
+	case p.shards[shard] <- task:
+		return true
+	default:
+	}
+
+	start := time.Now()
+	select {
+	case p.shards[shard] <- task:
+	case <-p.done:
+		p.queued.Add(-1)
+		p.rejected.Add(1)
+		return false
+	case <-ctx.Done():
+		p.queued.Add(-1)
+		p.rejected.Add(1)
+		return false
+	}
+	waited := time.Since(start)
+	p.blocked.Add(1)
+	p.blockedTime.Add(int64(waited))
+	if p.OnBlocked != nil {
+		p.OnBlocked(shard, waited)
+	}
+	return true
+}
+
+func (p *Pool) stopping() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close перестаёт принимать задачи; уже поставленные в очередь будут выполнены.
+// Submit, ждущие места в очереди, сразу получают false.
+func (p *Pool) Close() {
+	p.closeDone.Do(func() { close(p.done) })
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	for _, shard := range p.shards {
+		close(shard)
+	}
+}
+
+// Wait дожидается выполнения всех задач после Close не дольше timeout;
+// false — не все успели завершиться.
+func (p *Pool) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Stats возвращает текущие метрики пула.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Workers:     len(p.shards),
+		QueueSize:   p.queueSize,
+		Queued:      p.queued.Load(),
+		InFlight:    p.inFlight.Load(),
+		Processed:   p.processed.Load(),
+		Blocked:     p.blocked.Load(),
+		BlockedTime: time.Duration(p.blockedTime.Load()),
+		Rejected:    p.rejected.Load(),
+	}
+}
+
+func (p *Pool) run(tasks <-chan func()) {
+	defer p.wg.Done()
+	for task := range tasks {
+		p.queued.Add(-1)
+		p.inFlight.Add(1)
+		runTask(task)
+		p.inFlight.Add(-1)
+		p.processed.Add(1)
+	}
+}
+
+// runTask выполняет задачу, перехватывая панику: упавшая задача не должна останавливать
+// обработчик и оставлять остальные задачи его очереди без исполнения.
+func runTask(task func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Паника в задаче обработчика", "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	task()
+}
@@ -0,0 +1,151 @@
+package dispatch_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"telegram-anonymous-bot/internal/dispatch"
+)
+
+func TestPerChatOrder(t *testing.T) {
+	pool := dispatch.New(4, 8)
+
+	var mu sync.Mutex
+	got := make(map[int64][]int)
+	for i := 0; i < 100; i++ {
+		chat := int64(i % 5)
+		n := i
+		pool.Submit(context.Background(), chat, func() {
+			mu.Lock()
+			got[chat] = append(got[chat], n)
+			mu.Unlock()
+		})
+	}
+	pool.Close()
+	if !pool.Wait(time.Second) {
+		t.Fatal("Pool did not drain")
+	}
+
+	for chat, seq := range got {
+		for i := 1; i < len(seq); i++ {
+			if seq[i] < seq[i-1] {
+				t.Errorf("Chat %d: tasks out of order: %v", chat, seq)
+				break
+			}
+		}
+	}
+	if stats := pool.Stats(); stats.Processed != 100 || stats.Queued != 0 || stats.InFlight != 0 {
+		t.Errorf("Unexpected stats after drain: %+v", stats)
+	}
+}
+
+func TestSlowChatDoesNotBlockOthers(t *testing.T) {
+	pool := dispatch.New(2, 4)
+	defer pool.Close()
+
+	release := make(chan struct{})
+	slow, fast := int64(0), int64(1)
+	if pool.Shard(slow) == pool.Shard(fast) {
+		t.Fatal("Test chats must map to different shards")
+	}
+	pool.Submit(context.Background(), slow, func() { <-release })
+
+	done := make(chan struct{})
+	pool.Submit(context.Background(), fast, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Task of another chat waited for the slow one")
+	}
+	close(release)
+}
+
+func TestBackPressure(t *testing.T) {
+	pool := dispatch.New(1, 1)
+	var blockedShard = -1
+	pool.OnBlocked = func(shard int, _ time.Duration) { blockedShard = shard }
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.Submit(context.Background(), 1, func() { close(started); <-release })
+	<-started
+	pool.Submit(context.Background(), 1, func() {}) // занимает единственное место в очереди
+
+	submitted := make(chan struct{})
+	go func() {
+		pool.Submit(context.Background(), 1, func() {})
+		close(submitted)
+	}()
+	select {
+	case <-submitted:
+		t.Fatal("Submit did not wait for a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-submitted
+	pool.Close()
+	pool.Wait(time.Second)
+
+	stats := pool.Stats()
+	if stats.Blocked != 1 || stats.BlockedTime <= 0 || blockedShard != 0 {
+		t.Errorf("Expected one blocked submit on shard 0, got %+v (shard %d)", stats, blockedShard)
+	}
+	if pool.Submit(context.Background(), 1, func() {}) || pool.Stats().Rejected != 1 {
+		t.Errorf("Expected Submit after Close to be rejected")
+	}
+}
+
+func TestBlockedSubmitGivesUp(t *testing.T) {
+	pool := dispatch.New(1, 1)
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	pool.Submit(context.Background(), 1, func() { close(started); <-release })
+	<-started
+	pool.Submit(context.Background(), 1, func() {}) // очередь заполнена, обработчик занят
+
+	// Отмена ctx освобождает ждущий Submit
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if pool.Submit(ctx, 1, func() {}) {
+		t.Error("Expected Submit to give up when ctx is done")
+	}
+
+	// Close не ждёт Submit, застрявший на заполненной очереди, и тот получает false
+	result := make(chan bool)
+	go func() { result <- pool.Submit(context.Background(), 1, func() {}) }()
+	time.Sleep(20 * time.Millisecond)
+	closed := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close hung on a blocked Submit")
+	}
+	if <-result {
+		t.Error("Expected the blocked Submit to be rejected by Close")
+	}
+	if stats := pool.Stats(); stats.Rejected != 2 || stats.Queued != 1 {
+		t.Errorf("Unexpected stats after rejected submits: %+v", stats)
+	}
+}
+
+func TestPanicDoesNotStopWorker(t *testing.T) {
+	pool := dispatch.New(1, 4)
+	defer pool.Close()
+
+	pool.Submit(context.Background(), 1, func() { panic("boom") })
+	done := make(chan struct{})
+	pool.Submit(context.Background(), 1, func() { close(done) })
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Worker stopped after a panicking task")
+	}
+}
@@ -0,0 +1,194 @@
+// Package filter — локальная фильтрация вопросов по словам, регулярным выражениям и лимитам.
+// Правила читаются из YAML-файла и могут перечитываться без перезапуска бота.
+package filter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Action — что сделать с вопросом, попавшим под правило.
+type Action string
+
+const (
+	ActionAllow  Action = ""       // правило не сработало
+	ActionTag    Action = "tag"    // сохранить вопрос и добавить темы
+	ActionHold   Action = "hold"   // сохранить вопрос, но задержать до проверки администратором
+	ActionReject Action = "reject" // не сохранять, ответить пользователю сообщением правила
+	ActionDrop   Action = "drop"   // не сохранять и ничего не отвечать
+)
+
+// strength определяет, какое действие побеждает при срабатывании нескольких правил.
+var strength = map[Action]int{
+	ActionAllow:  0,
+	ActionTag:    1,
+	ActionHold:   2,
+	ActionReject: 3,
+	ActionDrop:   4,
+}
+
+// Rule — одно правило фильтра. Правило срабатывает, если выполнено любое из условий.
+type Rule struct {
+	Name      string   `yaml:"name"`
+	Words     []string `yaml:"words"`      // подстроки без учёта регистра
+	Regexes   []string `yaml:"regexes"`    // регулярные выражения Go (RE2)
+	MaxLinks  int      `yaml:"max_links"`  // больше ссылок — срабатывание (0 — не проверять)
+	MaxLength int      `yaml:"max_length"` // длиннее (в символах) — срабатывание (0 — не проверять)
+	Action    Action   `yaml:"action"`
+	Message   string   `yaml:"message"` // ответ пользователю для reject
+	Tags      []string `yaml:"tags"`    // темы для tag
+
+	compiled []*regexp.Regexp
+}
+
+// Rules — содержимое файла правил.
+type Rules struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Decision — итог проверки текста.
+type Decision struct {
+	Action  Action
+	Rules   []string // имена сработавших правил
+	Message string   // сообщение для пользователя (reject); пусто — стандартное сообщение бота
+	Tags    []string
+}
+
+var linkRe = regexp.MustCompile(`(?i)(https?://|www\.|t\.me/|@\w{5,})`)
+
+// Filter — потокобезопасный набор правил с возможностью перезагрузки из файла.
+type Filter struct {
+	path string
+
+	mu    sync.RWMutex
+	rules []*Rule
+}
+
+// Load читает правила из файла. Пустой путь — фильтр без правил (пропускает всё).
+func Load(path string) (*Filter, error) {
+	f := &Filter{path: path}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// New создаёт фильтр из уже готовых правил (для тестов и встраивания).
+func New(rules []*Rule) (*Filter, error) {
+	if err := compile(rules); err != nil {
+		return nil, err
+	}
+	return &Filter{rules: rules}, nil
+}
+
+// Reload перечитывает файл правил. При ошибке продолжают действовать прежние правила.
+func (f *Filter) Reload() error {
+	if f.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения правил фильтра: %w", err)
+	}
+	var parsed Rules
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return fmt.Errorf("ошибка разбора правил фильтра: %w", err)
+	}
+	if err := compile(parsed.Rules); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.rules = parsed.Rules
+	f.mu.Unlock()
+	return nil
+}
+
+// Configured сообщает, задан ли фильтр: есть файл правил или правила переданы в New.
+// Load("") возвращает пустой фильтр, который ничего не проверяет.
+func (f *Filter) Configured() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.path != "" || len(f.rules) > 0
+}
+
+// Len возвращает число загруженных правил.
+func (f *Filter) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.rules)
+}
+
+// Check проверяет текст всеми правилами. Побеждает самое строгое действие, темы суммируются.
+func (f *Filter) Check(text string) Decision {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var d Decision
+	lower := strings.ToLower(text)
+	for _, r := range f.rules {
+		if !r.matches(text, lower) {
+			continue
+		}
+		d.Rules = append(d.Rules, r.Name)
+		for _, tag := range r.Tags {
+			if !slices.Contains(d.Tags, tag) {
+				d.Tags = append(d.Tags, tag)
+			}
+		}
+		if strength[r.Action] > strength[d.Action] {
+			d.Action = r.Action
+			d.Message = r.Message
+		}
+	}
+	return d
+}
+
+func (r *Rule) matches(text, lower string) bool {
+	if r.MaxLength > 0 && utf8.RuneCountInString(text) > r.MaxLength {
+		return true
+	}
+	if r.MaxLinks > 0 && len(linkRe.FindAllString(text, -1)) > r.MaxLinks {
+		return true
+	}
+	for _, w := range r.Words {
+		if w != "" && strings.Contains(lower, strings.ToLower(w)) {
+			return true
+		}
+	}
+	for _, re := range r.compiled {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func compile(rules []*Rule) error {
+	for i, r := range rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if _, ok := strength[r.Action]; !ok || r.Action == ActionAllow {
+			return fmt.Errorf("правило %q: неизвестное действие %q", r.Name, r.Action)
+		}
+
+		r.compiled = r.compiled[:0]
+		for _, expr := range r.Regexes {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("правило %q: %w", r.Name, err)
+			}
+			r.compiled = append(r.compiled, re)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package filter_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"telegram-anonymous-bot/internal/filter"
+)
+
+const testRules = `
+rules:
+  - name: too-long
+    max_length: 50
+    action: reject
+    message: "Слишком длинно"
+  - name: links
+    max_links: 1
+    action: hold
+  - name: ads
+    words: ["КАЗИНО"]
+    regexes: ['(?i)промо\p{L}*']
+    action: drop
+  - name: exams
+    words: ["экзамен"]
+    action: tag
+    tags: ["учёба"]
+`
+
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+func TestCheck(t *testing.T) {
+	f, err := filter.Load(writeRules(t, testRules))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	tests := []struct {
+		text   string
+		action filter.Action
+		rules  []string
+		tags   []string
+	}{
+		{text: "Когда экзамен?", action: filter.ActionTag, rules: []string{"exams"}, tags: []string{"учёба"}},
+		{text: "Как дела?", action: filter.ActionAllow},
+		{text: "см. https://a.ru и https://b.ru", action: filter.ActionHold, rules: []string{"links"}},
+		{text: "Лучшее казино", action: filter.ActionDrop, rules: []string{"ads"}},
+		{text: "Промокод к экзамену", action: filter.ActionDrop, rules: []string{"ads", "exams"}, tags: []string{"учёба"}},
+		{text: strings.Repeat("я", 51), action: filter.ActionReject, rules: []string{"too-long"}},
+	}
+
+	for _, tt := range tests {
+		d := f.Check(tt.text)
+		if d.Action != tt.action || !reflect.DeepEqual(d.Rules, tt.rules) || !reflect.DeepEqual(d.Tags, tt.tags) {
+			t.Errorf("Check(%q) = %+v, expected action=%q rules=%v tags=%v", tt.text, d, tt.action, tt.rules, tt.tags)
+		}
+	}
+
+	if d := f.Check(strings.Repeat("я", 51)); d.Message != "Слишком длинно" {
+		t.Errorf("Expected rule message for reject, got %q", d.Message)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := writeRules(t, testRules)
+	f, err := filter.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Ошибочный файл не заменяет действующие правила
+	if err := os.WriteFile(path, []byte("rules:\n  - name: bad\n    action: explode\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := f.Reload(); err == nil {
+		t.Fatalf("Expected error for unknown action")
+	}
+	if f.Len() != 4 {
+		t.Errorf("Expected previous 4 rules to stay active, got %d", f.Len())
+	}
+
+	if err := os.WriteFile(path, []byte("rules:\n  - words: [\"привет\"]\n    action: hold\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if d := f.Check("Привет!"); d.Action != filter.ActionHold || d.Rules[0] != "rule-1" {
+		t.Errorf("Expected reloaded rule to hold, got %+v", d)
+	}
+	if !f.Configured() {
+		t.Error("Expected a filter with a rules file to be configured")
+	}
+}
+
+func TestEmptyPath(t *testing.T) {
+	f, err := filter.Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if d := f.Check("что угодно"); d.Action != filter.ActionAllow {
+		t.Errorf("Expected empty filter to allow everything, got %+v", d)
+	}
+	if f.Configured() {
+		t.Error("Expected a filter without a rules file to be unconfigured")
+	}
+}
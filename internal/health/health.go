@@ -0,0 +1,143 @@
+// Package health — проверки живости и готовности процесса для оркестратора: /healthz отвечает,
+// пока процесс жив, /readyz — только когда все компоненты (база, Telegram, обработка
+// обновлений) в порядке. Оба отдают JSON с состоянием, /readyz — 503 при любой ошибке.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// DefaultTimeout — сколько ждать каждую проверку.
+const DefaultTimeout = 5 * time.Second
+
+// Check проверяет компонент; nil — компонент в порядке.
+type Check func(ctx context.Context) error
+
+// Component — состояние одного компонента в ответе /readyz.
+type Component struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report — ответ /healthz и /readyz.
+type Report struct {
+	Status     string               `json:"status"`
+	Uptime     string               `json:"uptime"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+// Checker хранит проверки компонентов. Безопасен для одновременного использования.
+type Checker struct {
+	// Timeout — сколько ждать каждую проверку; 0 — DefaultTimeout.
+	Timeout time.Duration
+
+	started time.Time
+	mu      sync.Mutex
+	checks  map[string]Check
+}
+
+func New() *Checker {
+	return &Checker{started: time.Now(), checks: make(map[string]Check)}
+}
+
+// Add добавляет (или заменяет) проверку компонента name.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Run выполняет все проверки параллельно и собирает отчёт.
+func (c *Checker) Run(ctx context.Context) Report {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c.mu.Lock()
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mu.Unlock()
+
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := c.live()
+	report.Components = make(map[string]Component, len(names))
+	for i, name := range names {
+		if errs[i] != nil {
+			report.Status = StatusFail
+			report.Components[name] = Component{Status: StatusFail, Error: errs[i].Error()}
+			continue
+		}
+		report.Components[name] = Component{Status: StatusOK}
+	}
+	return report
+}
+
+// run выполняет проверку, но не дольше ctx: зависшая проверка не задерживает ответ.
+func run(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Checker) live() Report {
+	return Report{Status: StatusOK, Uptime: time.Since(c.started).Round(time.Second).String()}
+}
+
+// LiveHandler отвечает на /healthz: процесс жив и обслуживает HTTP. Компоненты не проверяет —
+// перезапуск не поможет, если недоступен Telegram.
+func (c *Checker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.live())
+	})
+}
+
+// ReadyHandler отвечает на /readyz: 200, если все проверки прошли, иначе 503.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.Run(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != StatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
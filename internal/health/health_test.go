@@ -0,0 +1,69 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"telegram-anonymous-bot/internal/health"
+)
+
+func TestReady(t *testing.T) {
+	checker := health.New()
+	checker.Timeout = 50 * time.Millisecond
+	checker.Add("storage", func(context.Context) error { return nil })
+
+	rec := httptest.NewRecorder()
+	checker.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 with all checks passing, got %d", rec.Code)
+	}
+
+	checker.Add("telegram", func(context.Context) error { return errors.New("getMe failed") })
+	checker.Add("updates", func(ctx context.Context) error {
+		<-make(chan struct{}) // зависшая проверка
+		return nil
+	})
+	rec = httptest.NewRecorder()
+	checker.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 with failing checks, got %d", rec.Code)
+	}
+
+	var report health.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Expected JSON report, got %q: %v", rec.Body.String(), err)
+	}
+	want := map[string]health.Component{
+		"storage":  {Status: health.StatusOK},
+		"telegram": {Status: health.StatusFail, Error: "getMe failed"},
+		"updates":  {Status: health.StatusFail, Error: context.DeadlineExceeded.Error()},
+	}
+	if report.Status != health.StatusFail || len(report.Components) != len(want) {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	for name, c := range want {
+		if report.Components[name] != c {
+			t.Errorf("Component %s: expected %+v, got %+v", name, c, report.Components[name])
+		}
+	}
+}
+
+func TestLive(t *testing.T) {
+	checker := health.New()
+	checker.Add("storage", func(context.Context) error { return errors.New("down") })
+
+	rec := httptest.NewRecorder()
+	checker.LiveHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected /healthz to ignore component checks, got %d", rec.Code)
+	}
+	var report health.Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil || report.Status != health.StatusOK || report.Uptime == "" {
+		t.Errorf("Unexpected /healthz body %q (%v)", rec.Body.String(), err)
+	}
+}
@@ -0,0 +1,208 @@
+// Package i18n — каталог текстов бота на нескольких языках.
+//
+// Тексты лежат в locales/<язык>.yaml: ключ — строка или набор форм множественного числа
+// (one, few, many, other). Параметры подставляются по имени: "Вопрос #{id}".
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLanguage — язык, на который откатываются недостающие переводы.
+const DefaultLanguage = "ru"
+
+//go:embed locales/*.yaml
+var locales embed.FS
+
+// Params — именованные параметры текста.
+type Params map[string]interface{}
+
+// entry — текст по ключу: одна форма или формы множественного числа.
+type entry struct {
+	text   string
+	plural map[string]string
+}
+
+func (e *entry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&e.text)
+	}
+	if err := node.Decode(&e.plural); err != nil {
+		return err
+	}
+	for form := range e.plural {
+		switch form {
+		case "one", "few", "many", "other":
+		default:
+			return fmt.Errorf("неизвестная форма множественного числа %q", form)
+		}
+	}
+	return nil
+}
+
+// Catalog хранит тексты всех языков.
+type Catalog struct {
+	fallback string
+	messages map[string]map[string]entry
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultCatalog *Catalog
+	defaultErr     error
+)
+
+// Default возвращает каталог из встроенных файлов locales/*.yaml.
+// Файлы проверяются тестами, поэтому ошибка разбора здесь — ошибка сборки.
+func Default() *Catalog {
+	defaultOnce.Do(func() {
+		defaultCatalog, defaultErr = Load(DefaultLanguage)
+	})
+	if defaultErr != nil {
+		panic(defaultErr)
+	}
+	return defaultCatalog
+}
+
+// Load разбирает встроенные файлы переводов. fallback — язык, на который
+// откатываются недостающие ключи; он должен быть среди загруженных.
+func Load(fallback string) (*Catalog, error) {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{fallback: fallback, messages: make(map[string]map[string]entry)}
+	for _, f := range files {
+		data, err := locales.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]entry
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("ошибка в файле переводов %s: %w", f.Name(), err)
+		}
+		c.messages[strings.TrimSuffix(f.Name(), ".yaml")] = messages
+	}
+
+	if _, ok := c.messages[fallback]; !ok {
+		return nil, fmt.Errorf("нет файла переводов для языка по умолчанию %q", fallback)
+	}
+	return c, nil
+}
+
+// Languages возвращает коды всех доступных языков.
+func (c *Catalog) Languages() []string {
+	langs := make([]string, 0, len(c.messages))
+	for lang := range c.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Supports сообщает, есть ли переводы на язык lang.
+func (c *Catalog) Supports(lang string) bool {
+	_, ok := c.messages[lang]
+	return ok
+}
+
+// Fallback возвращает язык по умолчанию.
+func (c *Catalog) Fallback() string {
+	return c.fallback
+}
+
+// Keys возвращает все ключи языка lang (для проверки полноты переводов).
+func (c *Catalog) Keys(lang string) []string {
+	keys := make([]string, 0, len(c.messages[lang]))
+	for key := range c.messages[lang] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// T возвращает текст по ключу на языке lang. Если перевода нет, используется язык
+// по умолчанию, а если нет и его — сам ключ, чтобы пропуск был заметен.
+func (c *Catalog) T(lang, key string, params ...Params) string {
+	e, lang, ok := c.lookup(lang, key)
+	if !ok {
+		return key
+	}
+	text := e.text
+	if e.plural != nil {
+		text = e.form(lang, 0)
+	}
+	return substitute(text, params)
+}
+
+// N возвращает текст с формой множественного числа для n; n доступно как параметр {count}.
+func (c *Catalog) N(lang, key string, n int, params ...Params) string {
+	e, lang, ok := c.lookup(lang, key)
+	if !ok {
+		return key
+	}
+	text := e.text
+	if e.plural != nil {
+		text = e.form(lang, n)
+	}
+	return substitute(text, append([]Params{{"count": n}}, params...))
+}
+
+func (c *Catalog) lookup(lang, key string) (entry, string, bool) {
+	if e, ok := c.messages[lang][key]; ok {
+		return e, lang, true
+	}
+	e, ok := c.messages[c.fallback][key]
+	return e, c.fallback, ok
+}
+
+func (e entry) form(lang string, n int) string {
+	if text, ok := e.plural[PluralForm(lang, n)]; ok {
+		return text
+	}
+	return e.plural["other"]
+}
+
+// PluralForm выбирает форму множественного числа по правилам CLDR для языка lang.
+func PluralForm(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch lang {
+	case "ru", "uk":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
+
+func substitute(text string, params []Params) string {
+	if len(params) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	var pairs []string
+	for _, p := range params {
+		for name, value := range p {
+			pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+		}
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
@@ -0,0 +1,74 @@
+package i18n_test
+
+import (
+	"strings"
+	"testing"
+
+	"telegram-anonymous-bot/internal/i18n"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	c := i18n.Default()
+
+	want := c.Keys(i18n.DefaultLanguage)
+	for _, lang := range c.Languages() {
+		have := make(map[string]bool)
+		for _, key := range c.Keys(lang) {
+			have[key] = true
+		}
+		for _, key := range want {
+			if !have[key] {
+				t.Errorf("Locale %q is missing key %q", lang, key)
+			}
+		}
+	}
+	if langs := c.Languages(); strings.Join(langs, ",") != "en,ru,uk" {
+		t.Errorf("Unexpected languages %v", langs)
+	}
+}
+
+func TestT(t *testing.T) {
+	c := i18n.Default()
+
+	if got := c.T("en", "common.answer_sent", i18n.Params{"id": 7}); got != "The answer to question 7 has been sent." {
+		t.Errorf("Unexpected text %q", got)
+	}
+	// Неизвестный язык откатывается на язык по умолчанию
+	if got := c.T("de", "common.answer_sent", i18n.Params{"id": 7}); got != "Ответ для вопроса 7 отправлен." {
+		t.Errorf("Expected fallback to Russian, got %q", got)
+	}
+	if got := c.T("en", "no.such.key"); got != "no.such.key" {
+		t.Errorf("Expected key for missing text, got %q", got)
+	}
+}
+
+func TestN(t *testing.T) {
+	c := i18n.Default()
+
+	tests := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{"ru", 1, "📋 1 неотвеченный вопрос"},
+		{"ru", 3, "📋 3 неотвеченных вопроса"},
+		{"ru", 11, "📋 11 неотвеченных вопросов"},
+		{"ru", 21, "📋 21 неотвеченный вопрос"},
+		{"uk", 5, "📋 5 питань без відповіді"},
+		{"en", 1, "📋 1 unanswered question"},
+		{"en", 2, "📋 2 unanswered questions"},
+	}
+	for _, tt := range tests {
+		if got := c.N(tt.lang, "digest.header", tt.n); got != tt.want {
+			t.Errorf("N(%s, %d) = %q, expected %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestPluralForm(t *testing.T) {
+	for n, want := range map[int]string{0: "many", 1: "one", 2: "few", 5: "many", 12: "many", 22: "few", 101: "one", 111: "many"} {
+		if got := i18n.PluralForm("ru", n); got != want {
+			t.Errorf("PluralForm(ru, %d) = %q, expected %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	DefaultCohereBaseURL = "https://api.cohere.ai"
+	DefaultCohereModel   = "command-xlarge-nightly"
+)
+
+// Cohere — провайдер Cohere API.
+type Cohere struct {
+	apiKey  string
+	baseURL string
+	opts    Options
+	client  *http.Client
+}
+
+func NewCohere(apiKey, baseURL string, opts Options, client *http.Client) *Cohere {
+	if baseURL == "" {
+		baseURL = DefaultCohereBaseURL
+	}
+	if opts.Model == "" {
+		opts.Model = DefaultCohereModel
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Cohere{apiKey: apiKey, baseURL: strings.TrimRight(baseURL, "/"), opts: opts, client: client}
+}
+
+func (c *Cohere) Name() string { return "cohere" }
+
+func (c *Cohere) Generate(ctx context.Context, prompt string) (string, error) {
+	payload := struct {
+		Model       string  `json:"model"`
+		Prompt      string  `json:"prompt"`
+		MaxTokens   int     `json:"max_tokens,omitempty"`
+		Temperature float64 `json:"temperature"`
+	}{
+		Model:       c.opts.Model,
+		Prompt:      prompt,
+		MaxTokens:   c.opts.MaxTokens,
+		Temperature: c.opts.Temperature,
+	}
+
+	var cohereResp struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	}
+	if err := c.post(ctx, "/generate", payload, &cohereResp); err != nil {
+		return "", err
+	}
+
+	if cohereResp.Text == "" {
+		return "", fmt.Errorf("пустой ответ от Cohere")
+	}
+	return cohereResp.Text, nil
+}
+
+// cohereChatRequest — тело запроса к Cohere Chat API.
+type cohereChatRequest struct {
+	Model       string       `json:"model"`
+	Message     string       `json:"message"`
+	Preamble    string       `json:"preamble,omitempty"`
+	ChatHistory []cohereTurn `json:"chat_history,omitempty"`
+	MaxTokens   int          `json:"max_tokens,omitempty"`
+	Temperature float64      `json:"temperature"`
+	Stream      bool         `json:"stream,omitempty"`
+}
+
+type cohereTurn struct {
+	Role    string `json:"role"`
+	Message string `json:"message"`
+}
+
+// chatRequest раскладывает диалог: последняя реплика идёт в message, остальные — в chat_history.
+func (c *Cohere) chatRequest(messages []Message) (cohereChatRequest, error) {
+	payload := cohereChatRequest{
+		Model:       c.opts.Model,
+		MaxTokens:   c.opts.MaxTokens,
+		Temperature: c.opts.Temperature,
+	}
+
+	if len(messages) == 0 {
+		return payload, fmt.Errorf("пустой диалог")
+	}
+	last := messages[len(messages)-1]
+	payload.Message = last.Content
+
+	for _, m := range messages[:len(messages)-1] {
+		switch m.Role {
+		case RoleSystem:
+			payload.Preamble = strings.TrimSpace(payload.Preamble + "\n" + m.Content)
+		case RoleAssistant:
+			payload.ChatHistory = append(payload.ChatHistory, cohereTurn{Role: "CHATBOT", Message: m.Content})
+		default:
+			payload.ChatHistory = append(payload.ChatHistory, cohereTurn{Role: "USER", Message: m.Content})
+		}
+	}
+	return payload, nil
+}
+
+// Chat использует Cohere Chat API.
+func (c *Cohere) Chat(ctx context.Context, messages []Message) (string, error) {
+	payload, err := c.chatRequest(messages)
+	if err != nil {
+		return "", err
+	}
+
+	var cohereResp struct {
+		Text string `json:"text"`
+	}
+	if err := c.post(ctx, "/v1/chat", payload, &cohereResp); err != nil {
+		return "", err
+	}
+	if cohereResp.Text == "" {
+		return "", fmt.Errorf("пустой ответ от Cohere")
+	}
+	return cohereResp.Text, nil
+}
+
+// ChatStream использует потоковый режим Cohere Chat API: ответ приходит строками JSON-событий.
+func (c *Cohere) ChatStream(ctx context.Context, messages []Message, onDelta func(delta string)) (string, error) {
+	payload, err := c.chatRequest(messages)
+	if err != nil {
+		return "", err
+	}
+	payload.Stream = true
+
+	resp, err := c.do(ctx, "/v1/chat", payload)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var event struct {
+			EventType string `json:"event_type"`
+			Text      string `json:"text"`
+		}
+		if err := json.Unmarshal(line, &event); err != nil {
+			return full.String(), fmt.Errorf("ошибка обработки потока Cohere: %w", err)
+		}
+		if event.EventType == "text-generation" && event.Text != "" {
+			full.WriteString(event.Text)
+			onDelta(event.Text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return full.String(), fmt.Errorf("ошибка чтения потока Cohere: %w", err)
+	}
+
+	if full.Len() == 0 {
+		return "", fmt.Errorf("пустой ответ от Cohere")
+	}
+	return full.String(), nil
+}
+
+// post отправляет JSON-запрос к Cohere API и разбирает ответ в out.
+func (c *Cohere) post(ctx context.Context, path string, payload, out interface{}) error {
+	resp, err := c.do(ctx, path, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("ошибка обработки JSON-ответа: %w", err)
+	}
+	return nil
+}
+
+// do отправляет JSON-запрос к Cohere API; при успехе тело ответа закрывает вызывающий.
+func (c *Cohere) do(ctx context.Context, path string, payload interface{}) (*http.Response, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка формирования JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания HTTP-запроса: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка отправки запроса к Cohere API: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("Cohere API error: %s", string(body))
+	}
+	return resp, nil
+}
@@ -0,0 +1,49 @@
+package llm
+
+import "unicode/utf8"
+
+// EstimateTokens грубо оценивает число токенов в тексте (~4 символа на токен).
+// Точный подсчёт зависит от модели, для обрезки истории хватает оценки.
+func EstimateTokens(s string) int {
+	return utf8.RuneCountInString(s)/4 + 1
+}
+
+// TrimToBudget оставляет системные сообщения и самые свежие реплики, укладывающиеся в budget токенов.
+// Последнее сообщение (текущий вопрос) сохраняется всегда, даже если оно одно превышает бюджет.
+func TrimToBudget(messages []Message, budget int) []Message {
+	if budget <= 0 || len(messages) == 0 {
+		return messages
+	}
+
+	var system []Message
+	var dialog []Message
+	for _, m := range messages {
+		if m.Role == RoleSystem {
+			system = append(system, m)
+		} else {
+			dialog = append(dialog, m)
+		}
+	}
+
+	used := 0
+	for _, m := range system {
+		used += EstimateTokens(m.Content)
+	}
+
+	start := len(dialog)
+	for i := len(dialog) - 1; i >= 0; i-- {
+		cost := EstimateTokens(dialog[i].Content)
+		if used+cost > budget && i != len(dialog)-1 {
+			break
+		}
+		used += cost
+		start = i
+	}
+
+	// История не должна начинаться с ответа ассистента без вопроса к нему
+	for start < len(dialog)-1 && dialog[start].Role == RoleAssistant {
+		start++
+	}
+
+	return append(system, dialog[start:]...)
+}
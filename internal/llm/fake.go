@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Fake — детерминированный провайдер для тестов и локальной разработки.
+// Без заданных ответов возвращает "echo: <запрос>".
+type Fake struct {
+	// Responses возвращаются по очереди; последний повторяется.
+	Responses []string
+	// Err, если задан, возвращается вместо ответа.
+	Err error
+
+	mu      sync.Mutex
+	prompts []string
+	chats   [][]Message
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) Generate(ctx context.Context, prompt string) (string, error) {
+	return f.reply(prompt)
+}
+
+// Chat отвечает на последнюю реплику; вся история доступна через Chats().
+func (f *Fake) Chat(ctx context.Context, messages []Message) (string, error) {
+	f.mu.Lock()
+	f.chats = append(f.chats, append([]Message(nil), messages...))
+	f.mu.Unlock()
+
+	if len(messages) == 0 {
+		return f.reply("")
+	}
+	return f.reply(messages[len(messages)-1].Content)
+}
+
+// ChatStream отдаёт ответ Chat по словам, имитируя потоковую генерацию.
+func (f *Fake) ChatStream(ctx context.Context, messages []Message, onDelta func(delta string)) (string, error) {
+	text, err := f.Chat(ctx, messages)
+	if err != nil {
+		return "", err
+	}
+	for _, word := range strings.SplitAfter(text, " ") {
+		if word != "" {
+			onDelta(word)
+		}
+	}
+	return text, nil
+}
+
+func (f *Fake) reply(prompt string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prompts = append(f.prompts, prompt)
+	if f.Err != nil {
+		return "", f.Err
+	}
+	if len(f.Responses) == 0 {
+		return "echo: " + prompt, nil
+	}
+
+	resp := f.Responses[0]
+	if len(f.Responses) > 1 {
+		f.Responses = f.Responses[1:]
+	}
+	return resp, nil
+}
+
+// Prompts возвращает все полученные запросы.
+func (f *Fake) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.prompts...)
+}
+
+// Chats возвращает истории, переданные в Chat.
+func (f *Fake) Chats() [][]Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]Message(nil), f.chats...)
+}
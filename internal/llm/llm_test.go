@@ -0,0 +1,217 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"telegram-anonymous-bot/internal/llm"
+)
+
+func TestCohereGenerate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/generate" {
+			t.Errorf("Expected /generate, got %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer key" {
+			t.Errorf("Unexpected Authorization header: %s", got)
+		}
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["model"] != "custom-model" || req["prompt"] != "Привет" {
+			t.Errorf("Unexpected request: %v", req)
+		}
+		_, _ = w.Write([]byte(`{"id":"1","text":"Здравствуйте"}`))
+	}))
+	defer srv.Close()
+
+	p := llm.NewCohere("key", srv.URL, llm.Options{Model: "custom-model", MaxTokens: 10}, srv.Client())
+	got, err := p.Generate(context.Background(), "Привет")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if got != "Здравствуйте" {
+		t.Errorf("Expected 'Здравствуйте', got %q", got)
+	}
+}
+
+func TestCohereError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"invalid api token"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	p := llm.NewCohere("bad", srv.URL, llm.Options{}, srv.Client())
+	if _, err := p.Generate(context.Background(), "Привет"); err == nil {
+		t.Errorf("Expected error for 401 response")
+	}
+}
+
+func TestOpenAIGenerate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Expected /v1/chat/completions, got %s", r.URL.Path)
+		}
+		var req struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "llama3" || len(req.Messages) != 1 || req.Messages[0].Content != "Привет" {
+			t.Errorf("Unexpected request: %+v", req)
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"Здравствуйте"}}]}`))
+	}))
+	defer srv.Close()
+
+	p, err := llm.New(llm.Settings{Provider: "local", BaseURL: srv.URL + "/v1", Model: "llama3"}, srv.Client())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	got, err := p.Generate(context.Background(), "Привет")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if got != "Здравствуйте" {
+		t.Errorf("Expected 'Здравствуйте', got %q", got)
+	}
+	if p.Name() != "local" {
+		t.Errorf("Expected provider name 'local', got %q", p.Name())
+	}
+}
+
+func TestFake(t *testing.T) {
+	f := &llm.Fake{Responses: []string{"первый", "второй"}}
+	for _, want := range []string{"первый", "второй", "второй"} {
+		got, err := f.Generate(context.Background(), "q")
+		if err != nil || got != want {
+			t.Errorf("Expected %q, got %q (err=%v)", want, got, err)
+		}
+	}
+	if len(f.Prompts()) != 3 {
+		t.Errorf("Expected 3 recorded prompts, got %d", len(f.Prompts()))
+	}
+}
+
+func TestNewUnknownProvider(t *testing.T) {
+	if _, err := llm.New(llm.Settings{Provider: "nope"}, nil); err == nil {
+		t.Errorf("Expected error for unknown provider")
+	}
+}
+
+func TestTrimToBudget(t *testing.T) {
+	long := strings.Repeat("а", 400) // ~100 токенов
+	messages := []llm.Message{
+		{Role: llm.RoleSystem, Content: "system"},
+		{Role: llm.RoleUser, Content: long},
+		{Role: llm.RoleAssistant, Content: long},
+		{Role: llm.RoleUser, Content: "коротко"},
+		{Role: llm.RoleAssistant, Content: "ответ"},
+		{Role: llm.RoleUser, Content: "вопрос"},
+	}
+
+	got := llm.TrimToBudget(messages, 50)
+	if len(got) != 4 {
+		t.Fatalf("Expected system prompt and 3 recent turns, got %d: %v", len(got), got)
+	}
+	if got[0].Role != llm.RoleSystem || got[1].Content != "коротко" || got[3].Content != "вопрос" {
+		t.Errorf("Unexpected trimmed history: %v", got)
+	}
+
+	// Текущий вопрос остаётся, даже если он один не помещается в бюджет
+	got = llm.TrimToBudget([]llm.Message{{Role: llm.RoleUser, Content: long}}, 10)
+	if len(got) != 1 {
+		t.Errorf("Expected the last message to be kept, got %v", got)
+	}
+}
+
+func TestCohereChat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat" {
+			t.Errorf("Expected /v1/chat, got %s", r.URL.Path)
+		}
+		var req struct {
+			Message     string `json:"message"`
+			Preamble    string `json:"preamble"`
+			ChatHistory []struct {
+				Role    string `json:"role"`
+				Message string `json:"message"`
+			} `json:"chat_history"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Message != "А сейчас?" || req.Preamble != "system" || len(req.ChatHistory) != 2 ||
+			req.ChatHistory[1].Role != "CHATBOT" {
+			t.Errorf("Unexpected request: %+v", req)
+		}
+		_, _ = w.Write([]byte(`{"text":"Сейчас тоже"}`))
+	}))
+	defer srv.Close()
+
+	p := llm.NewCohere("key", srv.URL, llm.Options{}, srv.Client())
+	got, err := p.Chat(context.Background(), []llm.Message{
+		{Role: llm.RoleSystem, Content: "system"},
+		{Role: llm.RoleUser, Content: "Идёт дождь?"},
+		{Role: llm.RoleAssistant, Content: "Да"},
+		{Role: llm.RoleUser, Content: "А сейчас?"},
+	})
+	if err != nil || got != "Сейчас тоже" {
+		t.Errorf("Expected 'Сейчас тоже', got %q (err=%v)", got, err)
+	}
+}
+
+func TestCohereChatStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Stream bool `json:"stream"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Errorf("Expected stream=true in request")
+		}
+		_, _ = w.Write([]byte(`{"event_type":"stream-start"}
+{"event_type":"text-generation","text":"При"}
+{"event_type":"text-generation","text":"вет"}
+{"event_type":"stream-end","finish_reason":"COMPLETE"}
+`))
+	}))
+	defer srv.Close()
+
+	p := llm.NewCohere("key", srv.URL, llm.Options{}, srv.Client())
+	var deltas []string
+	got, err := llm.StreamChat(context.Background(), p, []llm.Message{{Role: llm.RoleUser, Content: "Привет"}},
+		func(d string) { deltas = append(deltas, d) })
+	if err != nil || got != "Привет" {
+		t.Fatalf("Expected 'Привет', got %q (err=%v)", got, err)
+	}
+	if len(deltas) != 2 {
+		t.Errorf("Expected 2 deltas, got %v", deltas)
+	}
+}
+
+func TestOpenAIChatStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{"Здрав", "ствуйте"} {
+			_, _ = w.Write([]byte(`data: {"choices":[{"index":0,"delta":{"content":"` + chunk + `"}}]}` + "\n\n"))
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	p := llm.NewOpenAI("key", srv.URL+"/v1", llm.Options{}, srv.Client())
+	var deltas []string
+	got, err := llm.StreamChat(context.Background(), p, []llm.Message{{Role: llm.RoleUser, Content: "Привет"}},
+		func(d string) { deltas = append(deltas, d) })
+	if err != nil || got != "Здравствуйте" {
+		t.Fatalf("Expected 'Здравствуйте', got %q (err=%v)", got, err)
+	}
+	if len(deltas) != 2 {
+		t.Errorf("Expected 2 deltas, got %v", deltas)
+	}
+}
@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+const (
+	DefaultOpenAIModel  = openai.GPT3Dot5Turbo
+	DefaultLocalBaseURL = "http://localhost:11434/v1" // Ollama
+)
+
+// OpenAI — провайдер для OpenAI и любых серверов с совместимым API (Ollama, llama.cpp, vLLM).
+type OpenAI struct {
+	client *openai.Client
+	opts   Options
+	// name — имя провайдера из LLM_PROVIDER для логов и метрик: "openai" или "local".
+	name string
+}
+
+func NewOpenAI(apiKey, baseURL string, opts Options, client *http.Client) *OpenAI {
+	cfg := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	if client != nil {
+		cfg.HTTPClient = client
+	}
+	if opts.Model == "" {
+		opts.Model = DefaultOpenAIModel
+	}
+	return &OpenAI{client: openai.NewClientWithConfig(cfg), opts: opts, name: "openai"}
+}
+
+func (o *OpenAI) Name() string { return o.name }
+
+func (o *OpenAI) Generate(ctx context.Context, prompt string) (string, error) {
+	return o.Chat(ctx, []Message{{Role: RoleUser, Content: prompt}})
+}
+
+func (o *OpenAI) Chat(ctx context.Context, messages []Message) (string, error) {
+	resp, err := o.client.CreateChatCompletion(ctx, o.request(messages))
+	if err != nil {
+		return "", fmt.Errorf("ошибка запроса к OpenAI API: %w", err)
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("пустой ответ от OpenAI")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+func (o *OpenAI) ChatStream(ctx context.Context, messages []Message, onDelta func(delta string)) (string, error) {
+	stream, err := o.client.CreateChatCompletionStream(ctx, o.request(messages))
+	if err != nil {
+		return "", fmt.Errorf("ошибка запроса к OpenAI API: %w", err)
+	}
+	defer stream.Close()
+
+	var full strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return full.String(), fmt.Errorf("ошибка чтения потока OpenAI API: %w", err)
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := resp.Choices[0].Delta.Content
+		full.WriteString(delta)
+		onDelta(delta)
+	}
+
+	if full.Len() == 0 {
+		return "", fmt.Errorf("пустой ответ от OpenAI")
+	}
+	return full.String(), nil
+}
+
+func (o *OpenAI) request(messages []Message) openai.ChatCompletionRequest {
+	req := openai.ChatCompletionRequest{
+		Model:       o.opts.Model,
+		MaxTokens:   o.opts.MaxTokens,
+		Temperature: float32(o.opts.Temperature),
+	}
+	for _, m := range messages {
+		req.Messages = append(req.Messages, openai.ChatCompletionMessage{Role: m.Role, Content: m.Content})
+	}
+	return req
+}
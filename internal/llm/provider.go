@@ -0,0 +1,72 @@
+// Package llm — единый интерфейс к языковым моделям (Cohere, OpenAI-совместимые API, фейк для тестов).
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Provider генерирует текст по запросу.
+type Provider interface {
+	// Name возвращает короткое имя провайдера для логов и сообщений об ошибках.
+	Name() string
+	// Generate — одиночный запрос без истории.
+	Generate(ctx context.Context, prompt string) (string, error)
+	// Chat — запрос с историей диалога; последнее сообщение — реплика пользователя.
+	Chat(ctx context.Context, messages []Message) (string, error)
+}
+
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message — одна реплика диалога.
+type Message struct {
+	Role    string
+	Content string
+}
+
+// Settings — параметры выбора и настройки провайдера.
+type Settings struct {
+	Provider    string // cohere | openai | local | fake
+	APIKey      string
+	BaseURL     string // пусто — адрес по умолчанию для провайдера
+	Model       string // пусто — модель по умолчанию для провайдера
+	MaxTokens   int
+	Temperature float64
+}
+
+// Options — параметры генерации, общие для всех провайдеров.
+type Options struct {
+	Model       string
+	MaxTokens   int
+	Temperature float64
+}
+
+// New создаёт провайдера по настройкам. HTTP-клиент (с прокси) передаётся из BotCore.
+func New(s Settings, client *http.Client) (Provider, error) {
+	opts := Options{Model: s.Model, MaxTokens: s.MaxTokens, Temperature: s.Temperature}
+
+	switch s.Provider {
+	case "", "cohere":
+		return NewCohere(s.APIKey, s.BaseURL, opts, client), nil
+	case "openai":
+		return NewOpenAI(s.APIKey, s.BaseURL, opts, client), nil
+	case "local":
+		// Ollama, llama.cpp и другие серверы с OpenAI-совместимым API
+		baseURL := s.BaseURL
+		if baseURL == "" {
+			baseURL = DefaultLocalBaseURL
+		}
+		local := NewOpenAI(s.APIKey, baseURL, opts, client)
+		local.name = "local"
+		return local, nil
+	case "fake":
+		return &Fake{}, nil
+	default:
+		return nil, fmt.Errorf("неизвестный LLM-провайдер %q", s.Provider)
+	}
+}
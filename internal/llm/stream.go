@@ -0,0 +1,24 @@
+package llm
+
+import "context"
+
+// Streamer — необязательное расширение Provider: ответ приходит частями по мере генерации.
+type Streamer interface {
+	// ChatStream вызывает onDelta для каждого нового фрагмента и возвращает полный текст.
+	ChatStream(ctx context.Context, messages []Message, onDelta func(delta string)) (string, error)
+}
+
+// StreamChat использует потоковый режим, если провайдер его поддерживает,
+// иначе ждёт полного ответа и передаёт его в onDelta одним фрагментом.
+func StreamChat(ctx context.Context, p Provider, messages []Message, onDelta func(delta string)) (string, error) {
+	if s, ok := p.(Streamer); ok {
+		return s.ChatStream(ctx, messages, onDelta)
+	}
+
+	text, err := p.Chat(ctx, messages)
+	if err != nil {
+		return "", err
+	}
+	onDelta(text)
+	return text, nil
+}
@@ -0,0 +1,233 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"telegram-anonymous-bot/internal/dispatch"
+)
+
+// Имена метрик бота. Это публичный интерфейс для дашбордов и алертов: не переименовывайте
+// метрики и их метки, а добавляйте новые.
+const (
+	// UpdatesTotal — входящие обновления Telegram; метка type: message, edited_message,
+	// callback_query или other.
+	UpdatesTotal = "tgbot_updates_total"
+	// CommandsTotal — обработанные команды и вопросы; метки command (имя команды, "message" —
+	// вопрос, "callback:<префикс>" — нажатие на кнопку) и outcome (Outcome*).
+	CommandsTotal = "tgbot_commands_total"
+	// CommandDuration — длительность обработки команды в секундах; метка command.
+	CommandDuration = "tgbot_command_duration_seconds"
+	// QuestionsCreatedTotal — сохранённые вопросы пользователей.
+	QuestionsCreatedTotal = "tgbot_questions_created_total"
+	// QuestionsAnsweredTotal — вопросы, на которые отправлен ответ.
+	QuestionsAnsweredTotal = "tgbot_questions_answered_total"
+	// AnswerLatency — время от вопроса до ответа на него в секундах.
+	AnswerLatency = "tgbot_answer_latency_seconds"
+	// TelegramRequestDuration — длительность запросов к Bot API в секундах; метка method.
+	TelegramRequestDuration = "tgbot_telegram_request_duration_seconds"
+	// TelegramErrorsTotal — неудачные запросы к Bot API; метка method.
+	TelegramErrorsTotal = "tgbot_telegram_errors_total"
+	// LLMRequestDuration — длительность запросов к нейросети в секундах; метки provider
+	// и operation (generate, chat, stream).
+	LLMRequestDuration = "tgbot_llm_request_duration_seconds"
+	// LLMErrorsTotal — неудачные запросы к нейросети; метки provider и operation.
+	LLMErrorsTotal = "tgbot_llm_errors_total"
+	// LLMTokensTotal — токены запросов (direction="prompt") и ответов (direction="completion");
+	// метка provider. Провайдеры не сообщают расход, поэтому это оценка llm.EstimateTokens.
+	LLMTokensTotal = "tgbot_llm_tokens_total"
+	// StorageQueryDuration — длительность запросов к базе в секундах; метка op (метод Storage).
+	StorageQueryDuration = "tgbot_storage_query_duration_seconds"
+	// StorageErrorsTotal — неудачные запросы к базе; метка op.
+	StorageErrorsTotal = "tgbot_storage_errors_total"
+	// WorkersQueued, WorkersInFlight — обновления, ожидающие в очередях обработчиков и
+	// обрабатываемые сейчас; WorkersProcessedTotal — обработанные с запуска;
+	// WorkersBlockedTotal — сколько раз приём ждал места в заполненной очереди.
+	WorkersQueued         = "tgbot_workers_queued"
+	WorkersInFlight       = "tgbot_workers_in_flight"
+	WorkersProcessedTotal = "tgbot_workers_processed_total"
+	WorkersBlockedTotal   = "tgbot_workers_blocked_total"
+)
+
+// Итог обработки команды — значение метки outcome в CommandsTotal.
+const (
+	OutcomeOK          = "ok"
+	OutcomeError       = "error"
+	OutcomePanic       = "panic"
+	OutcomeDenied      = "denied"
+	OutcomeBanned      = "banned"
+	OutcomeRateLimited = "rate_limited"
+)
+
+// Set — метрики бота. Методы nil-безопасны: без Set (в тестах) метрики просто не собираются.
+type Set struct {
+	registry *Registry
+
+	updates           *Counter
+	commands          *Counter
+	commandDuration   *Histogram
+	questionsCreated  *Counter
+	questionsAnswered *Counter
+	answerLatency     *Histogram
+	telegramDuration  *Histogram
+	telegramErrors    *Counter
+	llmDuration       *Histogram
+	llmErrors         *Counter
+	llmTokens         *Counter
+	storageDuration   *Histogram
+	storageErrors     *Counter
+
+	mu      sync.Mutex
+	workers func() dispatch.Stats
+}
+
+// answerBuckets — границы времени ответа на вопрос: от минуты до недели.
+var answerBuckets = []float64{60, 300, 900, 3600, 3 * 3600, 6 * 3600, 12 * 3600, 86400, 3 * 86400, 7 * 86400}
+
+// storageBuckets — границы длительности запросов к SQLite: от 0,1 мс до секунды.
+var storageBuckets = []float64{0.0001, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+
+func New() *Set {
+	r := NewRegistry()
+	s := &Set{
+		registry:          r,
+		updates:           r.Counter(UpdatesTotal, "Входящие обновления Telegram по типу.", "type"),
+		commands:          r.Counter(CommandsTotal, "Обработанные команды и вопросы по итогу.", "command", "outcome"),
+		commandDuration:   r.Histogram(CommandDuration, "Длительность обработки команды, с.", nil, "command"),
+		questionsCreated:  r.Counter(QuestionsCreatedTotal, "Сохранённые вопросы."),
+		questionsAnswered: r.Counter(QuestionsAnsweredTotal, "Отвеченные вопросы."),
+		answerLatency:     r.Histogram(AnswerLatency, "Время от вопроса до ответа, с.", answerBuckets),
+		telegramDuration:  r.Histogram(TelegramRequestDuration, "Длительность запросов к Bot API, с.", nil, "method"),
+		telegramErrors:    r.Counter(TelegramErrorsTotal, "Неудачные запросы к Bot API.", "method"),
+		llmDuration:       r.Histogram(LLMRequestDuration, "Длительность запросов к нейросети, с.", nil, "provider", "operation"),
+		llmErrors:         r.Counter(LLMErrorsTotal, "Неудачные запросы к нейросети.", "provider", "operation"),
+		llmTokens:         r.Counter(LLMTokensTotal, "Оценка токенов запросов и ответов нейросети.", "provider", "direction"),
+		storageDuration:   r.Histogram(StorageQueryDuration, "Длительность запросов к базе, с.", storageBuckets, "op"),
+		storageErrors:     r.Counter(StorageErrorsTotal, "Неудачные запросы к базе.", "op"),
+	}
+	r.GaugeFunc(WorkersQueued, "Обновления в очередях обработчиков.", func() float64 { return float64(s.workerStats().Queued) })
+	r.GaugeFunc(WorkersInFlight, "Обновления, обрабатываемые сейчас.", func() float64 { return float64(s.workerStats().InFlight) })
+	r.CounterFunc(WorkersProcessedTotal, "Обработанные обновления.", func() float64 { return float64(s.workerStats().Processed) })
+	r.CounterFunc(WorkersBlockedTotal, "Ожидания места в заполненной очереди.", func() float64 { return float64(s.workerStats().Blocked) })
+	return s
+}
+
+// Registry возвращает реестр, в котором можно зарегистрировать дополнительные метрики.
+func (s *Set) Registry() *Registry {
+	return s.registry
+}
+
+// ServeHTTP отдаёт метрики в текстовом формате Prometheus.
+func (s *Set) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.registry.ServeHTTP(w, r)
+}
+
+// Workers задаёт источник метрик пула обработчиков обновлений (последний заданный).
+func (s *Set) Workers(stats func() dispatch.Stats) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workers = stats
+}
+
+func (s *Set) workerStats() dispatch.Stats {
+	s.mu.Lock()
+	stats := s.workers
+	s.mu.Unlock()
+	if stats == nil {
+		return dispatch.Stats{}
+	}
+	return stats()
+}
+
+// Update учитывает входящее обновление типа kind.
+func (s *Set) Update(kind string) {
+	if s == nil {
+		return
+	}
+	s.updates.Inc(kind)
+}
+
+// Command учитывает обработку команды с итогом outcome.
+func (s *Set) Command(name, outcome string, d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.commands.Inc(name, outcome)
+	s.commandDuration.Observe(d.Seconds(), name)
+}
+
+// QuestionCreated учитывает сохранённый вопрос.
+func (s *Set) QuestionCreated() {
+	if s == nil {
+		return
+	}
+	s.questionsCreated.Inc()
+}
+
+// QuestionAnswered учитывает ответ на вопрос, заданный at (нулевое время — без задержки).
+func (s *Set) QuestionAnswered(at time.Time) {
+	if s == nil {
+		return
+	}
+	s.questionsAnswered.Inc()
+	if !at.IsZero() {
+		s.answerLatency.Observe(time.Since(at).Seconds())
+	}
+}
+
+// TelegramRequest учитывает запрос к Bot API.
+func (s *Set) TelegramRequest(method string, d time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.telegramDuration.Observe(d.Seconds(), method)
+	if err != nil {
+		s.telegramErrors.Inc(method)
+	}
+}
+
+// LLMRequest учитывает запрос к нейросети и оценку токенов в нём и в ответе.
+func (s *Set) LLMRequest(provider, operation string, d time.Duration, prompt, completion int, err error) {
+	if s == nil {
+		return
+	}
+	s.llmDuration.Observe(d.Seconds(), provider, operation)
+	s.llmTokens.Add(float64(prompt), provider, "prompt")
+	if err != nil {
+		s.llmErrors.Inc(provider, operation)
+		return
+	}
+	s.llmTokens.Add(float64(completion), provider, "completion")
+}
+
+// StorageQuery учитывает запрос к базе.
+func (s *Set) StorageQuery(op string, d time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.storageDuration.Observe(d.Seconds(), op)
+	if err != nil {
+		s.storageErrors.Inc(op)
+	}
+}
+
+type outcomeKey struct{}
+
+// WithOutcome возвращает context, в котором обработка команды может отметить свой итог
+// через SetOutcome, и указатель на этот итог (по умолчанию OutcomeOK).
+func WithOutcome(ctx context.Context) (context.Context, *string) {
+	outcome := OutcomeOK
+	return context.WithValue(ctx, outcomeKey{}, &outcome), &outcome
+}
+
+// SetOutcome отмечает итог обработки команды. Без WithOutcome ничего не делает.
+func SetOutcome(ctx context.Context, outcome string) {
+	if p, ok := ctx.Value(outcomeKey{}).(*string); ok {
+		*p = outcome
+	}
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"telegram-anonymous-bot/internal/llm"
+)
+
+// LLM оборачивает провайдера нейросети: длительность, ошибки и оценка токенов каждого
+// запроса попадают в LLMRequestDuration, LLMErrorsTotal и LLMTokensTotal. Потоковый режим
+// провайдера сохраняется.
+func LLM(next llm.Provider, set *Set) llm.Provider {
+	return &instrumentedLLM{next: next, set: set}
+}
+
+type instrumentedLLM struct {
+	next llm.Provider
+	set  *Set
+}
+
+func (p *instrumentedLLM) Name() string {
+	return p.next.Name()
+}
+
+func (p *instrumentedLLM) Generate(ctx context.Context, prompt string) (string, error) {
+	start := time.Now()
+	text, err := p.next.Generate(ctx, prompt)
+	p.observe("generate", start, llm.EstimateTokens(prompt), text, err)
+	return text, err
+}
+
+func (p *instrumentedLLM) Chat(ctx context.Context, messages []llm.Message) (string, error) {
+	start := time.Now()
+	text, err := p.next.Chat(ctx, messages)
+	p.observe("chat", start, promptTokens(messages), text, err)
+	return text, err
+}
+
+func (p *instrumentedLLM) ChatStream(ctx context.Context, messages []llm.Message, onDelta func(delta string)) (string, error) {
+	start := time.Now()
+	text, err := llm.StreamChat(ctx, p.next, messages, onDelta)
+	p.observe("stream", start, promptTokens(messages), text, err)
+	return text, err
+}
+
+func (p *instrumentedLLM) observe(operation string, start time.Time, prompt int, text string, err error) {
+	p.set.LLMRequest(p.next.Name(), operation, time.Since(start), prompt, llm.EstimateTokens(text), err)
+}
+
+func promptTokens(messages []llm.Message) int {
+	n := 0
+	for _, m := range messages {
+		n += llm.EstimateTokens(m.Content)
+	}
+	return n
+}
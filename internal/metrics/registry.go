@@ -0,0 +1,311 @@
+// Package metrics — метрики бота в текстовом формате Prometheus (version 0.0.4): счётчики,
+// гистограммы и значения, которые вычисляются при каждом запросе /metrics. Клиентская
+// библиотека Prometheus не нужна — формат простой, а метрик немного.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType — тип ответа /metrics.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets — границы гистограмм длительности в секундах: от 5 мс до 1 минуты.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// Registry хранит метрики и отдаёт их по HTTP. Безопасен для одновременного использования.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.metrics[name]; ok {
+		panic(fmt.Sprintf("метрика %s уже зарегистрирована", name))
+	}
+	r.metrics[name] = m
+}
+
+// WriteTo пишет все метрики в порядке имён.
+func (r *Registry) WriteTo(out io.Writer) (int64, error) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]metric, len(names))
+	for i, name := range names {
+		list[i] = r.metrics[name]
+	}
+	r.mu.Unlock()
+
+	cw := &countingWriter{w: out}
+	w := bufio.NewWriter(cw)
+	for _, m := range list {
+		m.write(w)
+	}
+	err := w.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP отдаёт метрики в текстовом формате Prometheus.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = r.WriteTo(w)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// desc — имя, описание и метки метрики.
+type desc struct {
+	name, help, kind string
+	labels           []string
+}
+
+func (d desc) header(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, d.kind)
+}
+
+// key склеивает значения меток в ключ серии; число значений должно совпадать с числом меток.
+func (d desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("метрика %s: ожидается %d меток, передано %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// series форматирует имя серии с метками; le — граница корзины гистограммы (пусто — нет).
+func (d desc) series(suffix string, values []string, le string) string {
+	var b strings.Builder
+	b.WriteString(d.name)
+	b.WriteString(suffix)
+	pairs := make([]string, 0, len(values)+1)
+	for i, v := range values {
+		pairs = append(pairs, d.labels[i]+`="`+labelEscaper.Replace(v)+`"`)
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	if len(pairs) > 0 {
+		b.WriteByte('{')
+		b.WriteString(strings.Join(pairs, ","))
+		b.WriteByte('}')
+	}
+	return b.String()
+}
+
+// Counter — счётчик, который только растёт, с сериями по значениям меток.
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labels []string
+	value  float64
+}
+
+// Counter регистрирует счётчик. Имя по соглашению Prometheus оканчивается на _total.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	c := &Counter{desc: desc{name: name, help: help, kind: "counter", labels: labels}, values: make(map[string]*counterSeries)}
+	r.register(name, c)
+	return c
+}
+
+// Inc увеличивает серию с метками values на единицу.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add увеличивает серию с метками values на v (v >= 0).
+func (c *Counter) Add(v float64, values ...string) {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.values[key]
+	if !ok {
+		s = &counterSeries{labels: slices.Clone(values)}
+		c.values[key] = s
+	}
+	s.value += v
+}
+
+// Value возвращает текущее значение серии (0 — серии ещё нет).
+func (c *Counter) Value(values ...string) float64 {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.values[key]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.header(w)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Без меток серия одна и видна сразу, даже нулевая
+	if len(c.labels) == 0 && len(c.values) == 0 {
+		fmt.Fprintf(w, "%s 0\n", c.name)
+	}
+	for _, key := range sortedKeys(c.values) {
+		s := c.values[key]
+		fmt.Fprintf(w, "%s %s\n", c.series("", s.labels, ""), formatFloat(s.value))
+	}
+}
+
+// Histogram — распределение значений (обычно длительностей в секундах) по корзинам.
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labels []string
+	counts []uint64 // не накопительные: counts[i] — значения в (buckets[i-1], buckets[i]]
+	count  uint64
+	sum    float64
+}
+
+// Histogram регистрирует гистограмму с границами корзин buckets (nil — DefaultBuckets).
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	h := &Histogram{
+		desc:    desc{name: name, help: help, kind: "histogram", labels: labels},
+		buckets: buckets,
+		values:  make(map[string]*histogramSeries),
+	}
+	r.register(name, h)
+	return h
+}
+
+// Observe добавляет значение v в серию с метками values.
+func (h *Histogram) Observe(v float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.values[key]
+	if !ok {
+		s = &histogramSeries{labels: slices.Clone(values), counts: make([]uint64, len(h.buckets))}
+		h.values[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// Count возвращает число наблюдений в серии.
+func (h *Histogram) Count(values ...string) uint64 {
+	key := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.values[key]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.header(w)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range sortedKeys(h.values) {
+		s := h.values[key]
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s %d\n", h.series("_bucket", s.labels, formatFloat(le)), cumulative)
+		}
+		fmt.Fprintf(w, "%s %d\n", h.series("_bucket", s.labels, "+Inf"), s.count)
+		fmt.Fprintf(w, "%s %s\n", h.series("_sum", s.labels, ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s %d\n", h.series("_count", s.labels, ""), s.count)
+	}
+}
+
+// funcMetric — значение без меток, вычисляемое при каждом чтении метрик.
+type funcMetric struct {
+	desc
+	f func() float64
+}
+
+// GaugeFunc регистрирует значение, которое может расти и убывать (длина очереди и т.п.).
+func (r *Registry) GaugeFunc(name, help string, f func() float64) {
+	r.register(name, &funcMetric{desc: desc{name: name, help: help, kind: "gauge"}, f: f})
+}
+
+// CounterFunc регистрирует счётчик, который ведётся в другом месте (например, в пуле обработчиков).
+func (r *Registry) CounterFunc(name, help string, f func() float64) {
+	r.register(name, &funcMetric{desc: desc{name: name, help: help, kind: "counter"}, f: f})
+}
+
+func (m *funcMetric) write(w *bufio.Writer) {
+	m.header(w)
+	fmt.Fprintf(w, "%s %s\n", m.name, formatFloat(m.f()))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
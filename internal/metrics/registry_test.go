@@ -0,0 +1,90 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"telegram-anonymous-bot/internal/llm"
+	"telegram-anonymous-bot/internal/metrics"
+)
+
+func TestRegistryFormat(t *testing.T) {
+	r := metrics.NewRegistry()
+	c := r.Counter("test_requests_total", "Запросы.\nВторая строка.", "method")
+	c.Inc("get")
+	c.Add(2, `say "hi"\n`)
+	r.Counter("test_plain_total", "Без меток.")
+	h := r.Histogram("test_duration_seconds", "Длительность.", []float64{1, 0.1}, "op")
+	h.Observe(0.05, "read")
+	h.Observe(0.5, "read")
+	h.Observe(5, "read")
+	r.GaugeFunc("test_queue", "Очередь.", func() float64 { return 3 })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); got != metrics.ContentType {
+		t.Errorf("Expected Prometheus content type, got %q", got)
+	}
+
+	want := `# HELP test_duration_seconds Длительность.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{op="read",le="0.1"} 1
+test_duration_seconds_bucket{op="read",le="1"} 2
+test_duration_seconds_bucket{op="read",le="+Inf"} 3
+test_duration_seconds_sum{op="read"} 5.55
+test_duration_seconds_count{op="read"} 3
+# HELP test_plain_total Без меток.
+# TYPE test_plain_total counter
+test_plain_total 0
+# HELP test_queue Очередь.
+# TYPE test_queue gauge
+test_queue 3
+# HELP test_requests_total Запросы.\nВторая строка.
+# TYPE test_requests_total counter
+test_requests_total{method="get"} 1
+test_requests_total{method="say \"hi\"\\n"} 2
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("Unexpected exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCounterLabelsMismatch(t *testing.T) {
+	c := metrics.NewRegistry().Counter("test_total", "", "a", "b")
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a wrong number of label values")
+		}
+	}()
+	c.Inc("only-one")
+}
+
+func TestLLM(t *testing.T) {
+	set := metrics.New()
+	provider := metrics.LLM(&llm.Fake{Err: errors.New("down")}, set)
+	if _, err := provider.Generate(context.Background(), "12345678"); err == nil {
+		t.Fatalf("Expected the provider error to pass through")
+	}
+	provider = metrics.LLM(&llm.Fake{}, set)
+	if _, ok := provider.(llm.Streamer); !ok {
+		t.Errorf("Expected the wrapper to keep streaming")
+	}
+	if _, err := provider.Generate(context.Background(), "12345678"); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	var out strings.Builder
+	set.Registry().WriteTo(&out)
+	for _, want := range []string{
+		`tgbot_llm_errors_total{provider="fake",operation="generate"} 1`,
+		`tgbot_llm_request_duration_seconds_count{provider="fake",operation="generate"} 2`,
+		`tgbot_llm_tokens_total{provider="fake",direction="prompt"} 6`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %s in metrics, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/storage"
+)
+
+// Storage оборачивает хранилище: длительность и ошибки каждого запроса попадают
+// в StorageQueryDuration и StorageErrorsTotal с именем метода в метке op.
+func Storage(next storage.Storage, set *Set) storage.Storage {
+	return &instrumentedStorage{next: next, set: set}
+}
+
+type instrumentedStorage struct {
+	next storage.Storage
+	set  *Set
+}
+
+func (s *instrumentedStorage) observe(op string, f func() error) error {
+	start := time.Now()
+	err := f()
+	s.set.StorageQuery(op, time.Since(start), err)
+	return err
+}
+
+func observeValue[T any](s *instrumentedStorage, op string, f func() (T, error)) (T, error) {
+	var v T
+	err := s.observe(op, func() (err error) {
+		v, err = f()
+		return err
+	})
+	return v, err
+}
+
+func (s *instrumentedStorage) SaveQuestion(question *models.Question) error {
+	return s.observe("SaveQuestion", func() error { return s.next.SaveQuestion(question) })
+}
+
+func (s *instrumentedStorage) GetQuestion(id int) (*models.Question, error) {
+	return observeValue(s, "GetQuestion", func() (*models.Question, error) { return s.next.GetQuestion(id) })
+}
+
+func (s *instrumentedStorage) GetAllQuestions() ([]*models.Question, error) {
+	return observeValue(s, "GetAllQuestions", func() ([]*models.Question, error) { return s.next.GetAllQuestions() })
+}
+
+func (s *instrumentedStorage) GetLastQuestionID() (int, error) {
+	return observeValue(s, "GetLastQuestionID", func() (int, error) { return s.next.GetLastQuestionID() })
+}
+
+func (s *instrumentedStorage) UpdateQuestion(question *models.Question) error {
+	return s.observe("UpdateQuestion", func() error { return s.next.UpdateQuestion(question) })
+}
+
+func (s *instrumentedStorage) CloseQuestion(question *models.Question) error {
+	var err error
+	// Уже закрытый вопрос — не ошибка базы
+	s.observe("CloseQuestion", func() error {
+		err = s.next.CloseQuestion(question)
+		if errors.Is(err, storage.ErrQuestionClosed) {
+			return nil
+		}
+		return err
+	})
+	return err
+}
+
+func (s *instrumentedStorage) UpdateClassification(id int, category string, tags []string) error {
+	return s.observe("UpdateClassification", func() error { return s.next.UpdateClassification(id, category, tags) })
+}
+
+func (s *instrumentedStorage) SetHeld(id int, held bool) error {
+	return s.observe("SetHeld", func() error { return s.next.SetHeld(id, held) })
+}
+
+func (s *instrumentedStorage) UpdateTranslation(id int, language, translation string) error {
+	return s.observe("UpdateTranslation", func() error { return s.next.UpdateTranslation(id, language, translation) })
+}
+
+func (s *instrumentedStorage) SaveTemplate(t *models.Template) error {
+	return s.observe("SaveTemplate", func() error { return s.next.SaveTemplate(t) })
+}
+
+func (s *instrumentedStorage) GetTemplate(id int) (*models.Template, error) {
+	return observeValue(s, "GetTemplate", func() (*models.Template, error) { return s.next.GetTemplate(id) })
+}
+
+func (s *instrumentedStorage) GetTemplateByName(name string) (*models.Template, error) {
+	return observeValue(s, "GetTemplateByName", func() (*models.Template, error) { return s.next.GetTemplateByName(name) })
+}
+
+func (s *instrumentedStorage) GetAllTemplates() ([]*models.Template, error) {
+	return observeValue(s, "GetAllTemplates", func() ([]*models.Template, error) { return s.next.GetAllTemplates() })
+}
+
+func (s *instrumentedStorage) DeleteTemplate(name string) error {
+	return s.observe("DeleteTemplate", func() error { return s.next.DeleteTemplate(name) })
+}
+
+func (s *instrumentedStorage) SaveDraft(d *models.Draft) error {
+	return s.observe("SaveDraft", func() error { return s.next.SaveDraft(d) })
+}
+
+func (s *instrumentedStorage) GetDraft(id int) (*models.Draft, error) {
+	return observeValue(s, "GetDraft", func() (*models.Draft, error) { return s.next.GetDraft(id) })
+}
+
+func (s *instrumentedStorage) DeleteDraft(id int) error {
+	return s.observe("DeleteDraft", func() error { return s.next.DeleteDraft(id) })
+}
+
+func (s *instrumentedStorage) AddChatMessage(m *models.ChatMessage) error {
+	return s.observe("AddChatMessage", func() error { return s.next.AddChatMessage(m) })
+}
+
+func (s *instrumentedStorage) GetChatMessages(userID, limit int) ([]*models.ChatMessage, error) {
+	return observeValue(s, "GetChatMessages", func() ([]*models.ChatMessage, error) { return s.next.GetChatMessages(userID, limit) })
+}
+
+func (s *instrumentedStorage) ClearChat(userID int) error {
+	return s.observe("ClearChat", func() error { return s.next.ClearChat(userID) })
+}
+
+func (s *instrumentedStorage) GetUserLanguage(userID int) (string, error) {
+	return observeValue(s, "GetUserLanguage", func() (string, error) { return s.next.GetUserLanguage(userID) })
+}
+
+func (s *instrumentedStorage) SetUserLanguage(userID int, language string) error {
+	return s.observe("SetUserLanguage", func() error { return s.next.SetUserLanguage(userID, language) })
+}
+
+func (s *instrumentedStorage) GetLastUpdateID() (int, error) {
+	return observeValue(s, "GetLastUpdateID", func() (int, error) { return s.next.GetLastUpdateID() })
+}
+
+func (s *instrumentedStorage) SetLastUpdateID(id int) error {
+	return s.observe("SetLastUpdateID", func() error { return s.next.SetLastUpdateID(id) })
+}
+
+func (s *instrumentedStorage) GetState(key string) (string, error) {
+	return observeValue(s, "GetState", func() (string, error) { return s.next.GetState(key) })
+}
+
+func (s *instrumentedStorage) SetState(key, value string) error {
+	return s.observe("SetState", func() error { return s.next.SetState(key, value) })
+}
+
+func (s *instrumentedStorage) BanUser(userID int, reason string) error {
+	return s.observe("BanUser", func() error { return s.next.BanUser(userID, reason) })
+}
+
+func (s *instrumentedStorage) UnbanUser(userID int) (bool, error) {
+	return observeValue(s, "UnbanUser", func() (bool, error) { return s.next.UnbanUser(userID) })
+}
+
+func (s *instrumentedStorage) IsBanned(userID int) (bool, error) {
+	return observeValue(s, "IsBanned", func() (bool, error) { return s.next.IsBanned(userID) })
+}
+
+func (s *instrumentedStorage) Ping() error {
+	return s.observe("Ping", s.next.Ping)
+}
+
+func (s *instrumentedStorage) Close() error {
+	return s.next.Close()
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Messenger — отправка запросов к Bot API (совпадает с core.Messenger).
+type Messenger interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
+
+// TelegramMessenger оборачивает отправку запросов к Bot API: длительность и ошибки каждого
+// запроса попадают в TelegramRequestDuration и TelegramErrorsTotal с методом Bot API в метке method.
+// Уже обёрнутый для того же set Messenger возвращается как есть.
+func TelegramMessenger(next Messenger, set *Set) Messenger {
+	if m, ok := next.(*instrumentedMessenger); ok && m.set == set {
+		return m
+	}
+	return &instrumentedMessenger{next: next, set: set}
+}
+
+type instrumentedMessenger struct {
+	next Messenger
+	set  *Set
+}
+
+func (m *instrumentedMessenger) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	start := time.Now()
+	msg, err := m.next.Send(c)
+	m.set.TelegramRequest(Method(c), time.Since(start), err)
+	return msg, err
+}
+
+func (m *instrumentedMessenger) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	start := time.Now()
+	resp, err := m.next.Request(c)
+	m.set.TelegramRequest(Method(c), time.Since(start), err)
+	return resp, err
+}
+
+// Method возвращает метод Bot API для запроса. Библиотека не раскрывает его, поэтому
+// известные запросы перечислены явно, а для остальных используется имя типа.
+func Method(c tgbotapi.Chattable) string {
+	switch c.(type) {
+	case tgbotapi.MessageConfig:
+		return "sendMessage"
+	case tgbotapi.PhotoConfig:
+		return "sendPhoto"
+	case tgbotapi.VideoConfig:
+		return "sendVideo"
+	case tgbotapi.CopyMessageConfig:
+		return "copyMessage"
+	case tgbotapi.EditMessageTextConfig:
+		return "editMessageText"
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return "editMessageReplyMarkup"
+	case tgbotapi.ChatActionConfig:
+		return "sendChatAction"
+	case tgbotapi.CallbackConfig:
+		return "answerCallbackQuery"
+	case tgbotapi.SetMyCommandsConfig:
+		return "setMyCommands"
+	case tgbotapi.DeleteMyCommandsConfig:
+		return "deleteMyCommands"
+	case tgbotapi.WebhookConfig:
+		return "setWebhook"
+	case tgbotapi.DeleteWebhookConfig:
+		return "deleteWebhook"
+	case tgbotapi.FileConfig:
+		return "getFile"
+	}
+	name := fmt.Sprintf("%T", c)
+	return strings.TrimPrefix(name[strings.LastIndex(name, ".")+1:], "*")
+}
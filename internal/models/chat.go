@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ChatMessage — реплика диалога пользователя с нейросетью (/ask).
+type ChatMessage struct {
+	ID        int
+	UserID    int
+	Role      string // user | assistant
+	Content   string
+	CreatedAt time.Time
+}
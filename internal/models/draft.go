@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Draft — черновик ответа, предложенный нейросетью и ожидающий решения администратора.
+type Draft struct {
+	ID         int
+	QuestionID int
+	Text       string
+	CreatedAt  time.Time
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Question struct {
 	ID        int
 	UserID    int
@@ -9,4 +11,22 @@ type Question struct {
 	Answer    string
 	FileID    string
 	MediaType string
+	CreatedAt time.Time
+	// Category и Tags проставляет классификатор (см. internal/moderation);
+	// пустая категория — вопрос ещё не классифицирован.
+	Category string
+	Tags     []string
+	// Held — вопрос задержан локальным фильтром до проверки администратором.
+	Held bool
+	// Language — язык автора (код ISO 639-1), Translation — перевод текста на язык администраторов;
+	// пустой перевод — вопрос уже на нужном языке или перевод выключен.
+	Language    string
+	Translation string
+	// Rejected — вопрос отклонён администратором без ответа; причина отклонения хранится в Answer.
+	Rejected bool
+}
+
+// Open сообщает, ждёт ли вопрос ответа: на него не ответили и его не отклонили.
+func (q *Question) Open() bool {
+	return !q.Answered && !q.Rejected
 }
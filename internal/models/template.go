@@ -0,0 +1,8 @@
+package models
+
+// Template — заготовка ответа, которую администратор может подставить в /answer.
+type Template struct {
+	ID   int
+	Name string
+	Text string
+}
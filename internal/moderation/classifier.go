@@ -0,0 +1,93 @@
+// Package moderation — классификация входящих вопросов нейросетью.
+package moderation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Категории вопросов. Пустая категория — вопрос ещё не классифицирован.
+const (
+	CategoryNormal  = "normal"
+	CategoryUrgent  = "urgent"
+	CategorySpam    = "spam"
+	CategoryAbusive = "abusive"
+)
+
+// maxTags ограничивает число тем, которые сохраняются у вопроса.
+const maxTags = 3
+
+// Result — итог классификации вопроса.
+type Result struct {
+	Category string
+	Tags     []string
+}
+
+// Prompt формирует запрос к нейросети для классификации текста вопроса.
+func Prompt(text string) string {
+	return fmt.Sprintf(`Классифицируй анонимный вопрос пользователя.
+Категории:
+- spam — реклама, бессмыслица, массовые рассылки;
+- abusive — оскорбления, угрозы, травля;
+- urgent — требует срочной реакции (опасность, кризисная ситуация, жёсткий срок);
+- normal — всё остальное.
+Добавь до %d коротких тем (одно-два слова, строчными буквами, на русском).
+Ответь только JSON без пояснений: {"category": "...", "tags": ["..."]}
+
+Вопрос:
+%s`, maxTags, text)
+}
+
+// Parse разбирает ответ нейросети. Неизвестная категория считается normal,
+// лишний текст вокруг JSON игнорируется.
+func Parse(reply string) (Result, error) {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return Result{}, fmt.Errorf("в ответе нейросети нет JSON: %q", reply)
+	}
+
+	var raw struct {
+		Category string   `json:"category"`
+		Tags     []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return Result{}, fmt.Errorf("ошибка обработки ответа нейросети: %w", err)
+	}
+
+	res := Result{Category: strings.ToLower(strings.TrimSpace(raw.Category))}
+	switch res.Category {
+	case CategoryNormal, CategoryUrgent, CategorySpam, CategoryAbusive:
+	default:
+		res.Category = CategoryNormal
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range raw.Tags {
+		tag = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(tag, "#")))
+		if tag == "" || seen[tag] || strings.Contains(tag, ",") {
+			continue
+		}
+		seen[tag] = true
+		res.Tags = append(res.Tags, tag)
+		if len(res.Tags) == maxTags {
+			break
+		}
+	}
+	return res, nil
+}
+
+// Priority задаёт порядок вопросов в /list: срочные сверху, спам в самом низу.
+func Priority(category string) int {
+	switch category {
+	case CategoryUrgent:
+		return 0
+	case CategoryAbusive:
+		return 2
+	case CategorySpam:
+		return 3
+	default:
+		return 1
+	}
+}
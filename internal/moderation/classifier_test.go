@@ -0,0 +1,48 @@
+package moderation_test
+
+import (
+	"reflect"
+	"testing"
+
+	"telegram-anonymous-bot/internal/moderation"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  moderation.Result
+	}{
+		{
+			name:  "plain JSON",
+			reply: `{"category": "urgent", "tags": ["учёба", "сессия"]}`,
+			want:  moderation.Result{Category: "urgent", Tags: []string{"учёба", "сессия"}},
+		},
+		{
+			name:  "JSON in prose with noisy tags",
+			reply: "Вот результат:\n```json\n{\"category\": \"Spam\", \"tags\": [\"#Реклама\", \"реклама\", \" \"]}\n```",
+			want:  moderation.Result{Category: "spam", Tags: []string{"реклама"}},
+		},
+		{
+			name:  "unknown category",
+			reply: `{"category": "question", "tags": []}`,
+			want:  moderation.Result{Category: "normal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := moderation.Parse(tt.reply)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := moderation.Parse("не знаю"); err == nil {
+		t.Errorf("Expected error for reply without JSON")
+	}
+}
@@ -0,0 +1,31 @@
+// internal/storage/bans.go
+
+package storage
+
+import "time"
+
+// BanUser запрещает пользователю писать боту; повторный бан обновляет причину.
+func (s *SQLiteStorage) BanUser(userID int, reason string) error {
+	_, err := s.db.Exec(`
+INSERT INTO bans (user_id, reason, created_at) VALUES (?, ?, ?)
+ON CONFLICT(user_id) DO UPDATE SET reason = excluded.reason
+`, userID, reason, time.Now())
+	return err
+}
+
+// UnbanUser снимает бан; false — пользователь не был забанен.
+func (s *SQLiteStorage) UnbanUser(userID int) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM bans WHERE user_id = ?`, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// IsBanned проверяет, забанен ли пользователь.
+func (s *SQLiteStorage) IsBanned(userID int) (bool, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM bans WHERE user_id = ?`, userID).Scan(&n)
+	return n > 0, err
+}
@@ -0,0 +1,62 @@
+// internal/storage/chats.go
+
+package storage
+
+import (
+	"time"
+
+	"telegram-anonymous-bot/internal/models"
+)
+
+func (s *SQLiteStorage) AddChatMessage(m *models.ChatMessage) error {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+
+	res, err := s.db.Exec(`
+INSERT INTO chat_messages (user_id, role, content, created_at)
+VALUES (?, ?, ?, ?)
+`, m.UserID, m.Role, m.Content, m.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	m.ID = int(id)
+	return nil
+}
+
+// GetChatMessages возвращает не более limit последних реплик пользователя в хронологическом порядке.
+func (s *SQLiteStorage) GetChatMessages(userID, limit int) ([]*models.ChatMessage, error) {
+	rows, err := s.db.Query(`
+SELECT id, user_id, role, content, created_at FROM (
+    SELECT id, user_id, role, content, created_at
+    FROM chat_messages
+    WHERE user_id = ?
+    ORDER BY id DESC
+    LIMIT ?
+) ORDER BY id
+`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*models.ChatMessage
+	for rows.Next() {
+		m := &models.ChatMessage{}
+		if err := rows.Scan(&m.ID, &m.UserID, &m.Role, &m.Content, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (s *SQLiteStorage) ClearChat(userID int) error {
+	_, err := s.db.Exec(`DELETE FROM chat_messages WHERE user_id = ?`, userID)
+	return err
+}
@@ -0,0 +1,44 @@
+// internal/storage/drafts.go
+
+package storage
+
+import (
+	"time"
+
+	"telegram-anonymous-bot/internal/models"
+)
+
+func (s *SQLiteStorage) SaveDraft(d *models.Draft) error {
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+
+	res, err := s.db.Exec(`
+INSERT INTO drafts (question_id, text, created_at)
+VALUES (?, ?, ?)
+`, d.QuestionID, d.Text, d.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	d.ID = int(id)
+	return nil
+}
+
+func (s *SQLiteStorage) GetDraft(id int) (*models.Draft, error) {
+	d := &models.Draft{}
+	row := s.db.QueryRow(`SELECT id, question_id, text, created_at FROM drafts WHERE id = ?`, id)
+	if err := row.Scan(&d.ID, &d.QuestionID, &d.Text, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *SQLiteStorage) DeleteDraft(id int) error {
+	_, err := s.db.Exec(`DELETE FROM drafts WHERE id = ?`, id)
+	return err
+}
@@ -4,7 +4,10 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"telegram-anonymous-bot/internal/models"
@@ -31,50 +34,97 @@ CREATE TABLE IF NOT EXISTS questions (
     answered INTEGER DEFAULT 0, -- 0 = false, 1 = true
     answer TEXT
 );
+
+CREATE TABLE IF NOT EXISTS templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    text TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS drafts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    question_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    role TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_user ON chat_messages (user_id, id);
+
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INTEGER PRIMARY KEY,
+    language TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS bot_state (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS bans (
+    user_id INTEGER PRIMARY KEY,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
 `
 	if _, err = db.Exec(createTable); err != nil {
 		return nil, err
 	}
 
+	if err = migrate(db); err != nil {
+		return nil, err
+	}
+
 	return &SQLiteStorage{db: db}, nil
 }
 
-func (s *SQLiteStorage) SaveQuestion(q *models.Question) error {
-	query := `
-INSERT INTO questions (user_id, username, text, file_id, media_type)
-VALUES (?, ?, ?, ?, ?)
-`
-	stmt, err := s.db.Prepare(query)
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
-	res, err := stmt.Exec(q.UserID, q.Username, q.Text, q.FileID, q.MediaType)
-	if err != nil {
-		return err
-	}
+// migrations — изменения схемы для баз, созданных предыдущими версиями бота.
+// Новые колонки добавляются только в конец списка.
+var migrations = []string{
+	`ALTER TABLE questions ADD COLUMN created_at TIMESTAMP`,
+	`ALTER TABLE questions ADD COLUMN category TEXT`,
+	`ALTER TABLE questions ADD COLUMN tags TEXT`, // через запятую
+	`ALTER TABLE questions ADD COLUMN held INTEGER DEFAULT 0`,
+	`ALTER TABLE questions ADD COLUMN language TEXT`,
+	`ALTER TABLE questions ADD COLUMN translation TEXT`,
+	`ALTER TABLE questions ADD COLUMN rejected INTEGER DEFAULT 0`,
+}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return err
+func migrate(db *sql.DB) error {
+	for _, m := range migrations {
+		if _, err := db.Exec(m); err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			return fmt.Errorf("migration %q: %w", m, err)
+		}
 	}
-	q.ID = int(id)
 	return nil
 }
 
-func (s *SQLiteStorage) GetQuestion(id int) (*models.Question, error) {
-	row := s.db.QueryRow(`
-SELECT id, user_id, username, text, file_id, media_type, answered, answer
-FROM questions
-WHERE id = ?
-`, id)
+const questionColumns = `id, user_id, username, text, file_id, media_type, answered, answer, created_at, category, tags, held, language, translation, rejected`
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+func scanQuestion(row rowScanner) (*models.Question, error) {
 	q := &models.Question{}
 	var answeredInt int
 	var answer sql.NullString
 	var fileID sql.NullString
 	var mediaType sql.NullString
+	var createdAt sql.NullTime
+	var category sql.NullString
+	var tags sql.NullString
+	var held sql.NullInt64
+	var language sql.NullString
+	var translation sql.NullString
+	var rejected sql.NullInt64
 
 	if err := row.Scan(
 		&q.ID,
@@ -85,6 +135,13 @@ WHERE id = ?
 		&mediaType,
 		&answeredInt,
 		&answer,
+		&createdAt,
+		&category,
+		&tags,
+		&held,
+		&language,
+		&translation,
+		&rejected,
 	); err != nil {
 		return nil, err
 	}
@@ -99,13 +156,63 @@ WHERE id = ?
 
 	if answer.Valid {
 		q.Answer = answer.String
-	} else {
-		q.Answer = ""
 	}
+	if createdAt.Valid {
+		q.CreatedAt = createdAt.Time
+	}
+	if category.Valid {
+		q.Category = category.String
+	}
+	if tags.Valid && tags.String != "" {
+		q.Tags = strings.Split(tags.String, ",")
+	}
+	q.Held = held.Valid && held.Int64 != 0
+	q.Language = language.String
+	q.Translation = translation.String
+	q.Rejected = rejected.Valid && rejected.Int64 != 0
 
 	return q, nil
 }
 
+func (s *SQLiteStorage) SaveQuestion(q *models.Question) error {
+	query := `
+INSERT INTO questions (user_id, username, text, file_id, media_type, created_at, category, tags, held, language, translation)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if q.CreatedAt.IsZero() {
+		q.CreatedAt = time.Now()
+	}
+
+	res, err := stmt.Exec(q.UserID, q.Username, q.Text, q.FileID, q.MediaType, q.CreatedAt,
+		q.Category, strings.Join(q.Tags, ","), q.Held, q.Language, q.Translation)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	q.ID = int(id)
+	return nil
+}
+
+func (s *SQLiteStorage) GetQuestion(id int) (*models.Question, error) {
+	row := s.db.QueryRow(`
+SELECT `+questionColumns+`
+FROM questions
+WHERE id = ?
+`, id)
+
+	return scanQuestion(row)
+}
+
 func (s *SQLiteStorage) GetLastQuestionID() (int, error) {
 	row := s.db.QueryRow("SELECT MAX(id) FROM questions")
 	var id sql.NullInt64
@@ -121,7 +228,7 @@ func (s *SQLiteStorage) GetLastQuestionID() (int, error) {
 func (s *SQLiteStorage) UpdateQuestion(q *models.Question) error {
 	stmt, err := s.db.Prepare(`
 UPDATE questions
-SET answered = ?, answer = ?
+SET answered = ?, answer = ?, rejected = ?
 WHERE id = ?
 `)
 	if err != nil {
@@ -134,13 +241,59 @@ WHERE id = ?
 		answeredVal = 1
 	}
 
-	_, err = stmt.Exec(answeredVal, q.Answer, q.ID)
+	_, err = stmt.Exec(answeredVal, q.Answer, q.Rejected, q.ID)
+	return err
+}
+
+// ErrQuestionClosed возвращается CloseQuestion, если на вопрос уже ответили или он отклонён.
+var ErrQuestionClosed = errors.New("вопрос уже закрыт")
+
+// CloseQuestion атомарно сохраняет ответ или отказ открытого вопроса: из двух
+// одновременных попыток закрыть вопрос успешна только одна.
+func (s *SQLiteStorage) CloseQuestion(q *models.Question) error {
+	res, err := s.db.Exec(`
+UPDATE questions
+SET answered = ?, answer = ?, rejected = ?
+WHERE id = ? AND answered = 0 AND rejected = 0
+`, q.Answered, q.Answer, q.Rejected, q.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrQuestionClosed
+	}
+	return nil
+}
+
+// UpdateClassification сохраняет категорию и темы вопроса.
+func (s *SQLiteStorage) UpdateClassification(id int, category string, tags []string) error {
+	_, err := s.db.Exec(`
+UPDATE questions
+SET category = ?, tags = ?
+WHERE id = ?
+`, category, strings.Join(tags, ","), id)
+	return err
+}
+
+// SetHeld задерживает вопрос до проверки или снимает задержку.
+func (s *SQLiteStorage) SetHeld(id int, held bool) error {
+	_, err := s.db.Exec(`UPDATE questions SET held = ? WHERE id = ?`, held, id)
+	return err
+}
+
+// UpdateTranslation сохраняет язык автора и перевод текста вопроса.
+func (s *SQLiteStorage) UpdateTranslation(id int, language, translation string) error {
+	_, err := s.db.Exec(`UPDATE questions SET language = ?, translation = ? WHERE id = ?`, language, translation, id)
 	return err
 }
 
 func (s *SQLiteStorage) GetAllQuestions() ([]*models.Question, error) {
 	rows, err := s.db.Query(`
-SELECT id, user_id, username, text, file_id, media_type, answered, answer
+SELECT ` + questionColumns + `
 FROM questions
 `)
 	if err != nil {
@@ -150,40 +303,10 @@ FROM questions
 
 	var questions []*models.Question
 	for rows.Next() {
-		q := &models.Question{}
-		var answeredInt int
-		var answer sql.NullString
-		var fileID sql.NullString
-		var mediaType sql.NullString
-
-		if err := rows.Scan(
-			&q.ID,
-			&q.UserID,
-			&q.Username,
-			&q.Text,
-			&fileID,
-			&mediaType,
-			&answeredInt,
-			&answer,
-		); err != nil {
+		q, err := scanQuestion(rows)
+		if err != nil {
 			return nil, err
 		}
-
-		if fileID.Valid {
-			q.FileID = fileID.String
-		}
-		if mediaType.Valid {
-			q.MediaType = mediaType.String
-		}
-
-		q.Answered = (answeredInt != 0)
-
-		if answer.Valid {
-			q.Answer = answer.String
-		} else {
-			q.Answer = ""
-		}
-
 		questions = append(questions, q)
 	}
 
@@ -1,6 +1,7 @@
 package storage_test
 
 import (
+	"errors"
 	"path/filepath"
 	"testing"
 
@@ -20,6 +21,7 @@ func createTestDB(t *testing.T) *storage.SQLiteStorage {
 	if err != nil {
 		t.Fatalf("Failed to create storage: %v", err)
 	}
+	t.Cleanup(func() { store.Close() })
 	return store
 }
 
@@ -64,6 +66,9 @@ func TestSaveAndGetQuestion(t *testing.T) {
 	if got.MediaType != "photo" {
 		t.Errorf("Expected MediaType=photo, got %s", got.MediaType)
 	}
+	if got.CreatedAt.IsZero() {
+		t.Errorf("Expected CreatedAt to be set")
+	}
 }
 
 func TestUpdateQuestion(t *testing.T) {
@@ -97,6 +102,37 @@ func TestUpdateQuestion(t *testing.T) {
 	}
 }
 
+func TestCloseQuestion(t *testing.T) {
+	store := createTestDB(t)
+
+	q := &models.Question{UserID: 999, Username: "close_tester", Text: "Close me!"}
+	if err := store.SaveQuestion(q); err != nil {
+		t.Fatalf("SaveQuestion failed: %v", err)
+	}
+
+	// Закрыть вопрос можно только один раз: второй ответ и отказ не перезаписывают первый
+	first := &models.Question{ID: q.ID, Answered: true, Answer: "First"}
+	if err := store.CloseQuestion(first); err != nil {
+		t.Fatalf("CloseQuestion failed: %v", err)
+	}
+	for _, again := range []*models.Question{
+		{ID: q.ID, Answered: true, Answer: "Second"},
+		{ID: q.ID, Rejected: true, Answer: "Reason"},
+	} {
+		if err := store.CloseQuestion(again); !errors.Is(err, storage.ErrQuestionClosed) {
+			t.Errorf("Expected ErrQuestionClosed, got %v", err)
+		}
+	}
+
+	got, err := store.GetQuestion(q.ID)
+	if err != nil {
+		t.Fatalf("GetQuestion failed: %v", err)
+	}
+	if !got.Answered || got.Rejected || got.Answer != "First" {
+		t.Errorf("Expected the first answer to stay, got %+v", got)
+	}
+}
+
 func TestGetAllQuestions(t *testing.T) {
 	store := createTestDB(t)
 
@@ -145,3 +181,265 @@ func TestGetLastQuestionID(t *testing.T) {
 		t.Errorf("Expected lastID=%d, got %d", q2.ID, lastID)
 	}
 }
+
+func TestTemplates(t *testing.T) {
+	store := createTestDB(t)
+
+	tpl := &models.Template{Name: "thanks", Text: "Спасибо за вопрос #{{.ID}}"}
+	if err := store.SaveTemplate(tpl); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+	if tpl.ID == 0 {
+		t.Fatalf("Expected template ID to be set, got 0")
+	}
+
+	// Повторное сохранение с тем же именем обновляет текст
+	if err := store.SaveTemplate(&models.Template{Name: "thanks", Text: "Спасибо!"}); err != nil {
+		t.Fatalf("SaveTemplate (update) failed: %v", err)
+	}
+	got, err := store.GetTemplateByName("thanks")
+	if err != nil {
+		t.Fatalf("GetTemplateByName failed: %v", err)
+	}
+	if got.ID != tpl.ID || got.Text != "Спасибо!" {
+		t.Errorf("Expected updated template with ID=%d, got %+v", tpl.ID, got)
+	}
+
+	all, err := store.GetAllTemplates()
+	if err != nil {
+		t.Fatalf("GetAllTemplates failed: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("Expected 1 template, got %d", len(all))
+	}
+
+	if err := store.DeleteTemplate("thanks"); err != nil {
+		t.Fatalf("DeleteTemplate failed: %v", err)
+	}
+	if err := store.DeleteTemplate("thanks"); err == nil {
+		t.Errorf("Expected error when deleting missing template")
+	}
+}
+
+func TestDrafts(t *testing.T) {
+	store := createTestDB(t)
+
+	d := &models.Draft{QuestionID: 5, Text: "Черновик"}
+	if err := store.SaveDraft(d); err != nil {
+		t.Fatalf("SaveDraft failed: %v", err)
+	}
+
+	got, err := store.GetDraft(d.ID)
+	if err != nil {
+		t.Fatalf("GetDraft failed: %v", err)
+	}
+	if got.QuestionID != 5 || got.Text != "Черновик" {
+		t.Errorf("Unexpected draft: %+v", got)
+	}
+
+	if err := store.DeleteDraft(d.ID); err != nil {
+		t.Fatalf("DeleteDraft failed: %v", err)
+	}
+	if _, err := store.GetDraft(d.ID); err == nil {
+		t.Errorf("Expected error for deleted draft")
+	}
+}
+
+func TestChatMessages(t *testing.T) {
+	store := createTestDB(t)
+
+	for _, content := range []string{"1", "2", "3"} {
+		if err := store.AddChatMessage(&models.ChatMessage{UserID: 1, Role: "user", Content: content}); err != nil {
+			t.Fatalf("AddChatMessage failed: %v", err)
+		}
+	}
+	_ = store.AddChatMessage(&models.ChatMessage{UserID: 2, Role: "user", Content: "чужое"})
+
+	// Последние две реплики в хронологическом порядке
+	got, err := store.GetChatMessages(1, 2)
+	if err != nil {
+		t.Fatalf("GetChatMessages failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Content != "2" || got[1].Content != "3" {
+		t.Errorf("Expected messages 2 and 3, got %+v", got)
+	}
+
+	if err := store.ClearChat(1); err != nil {
+		t.Fatalf("ClearChat failed: %v", err)
+	}
+	if got, _ := store.GetChatMessages(1, 10); len(got) != 0 {
+		t.Errorf("Expected empty history after ClearChat, got %d", len(got))
+	}
+	if got, _ := store.GetChatMessages(2, 10); len(got) != 1 {
+		t.Errorf("ClearChat must not touch other users, got %d", len(got))
+	}
+}
+
+func TestUpdateClassification(t *testing.T) {
+	store := createTestDB(t)
+
+	q := &models.Question{UserID: 1, Username: "u1", Text: "Помогите"}
+	if err := store.SaveQuestion(q); err != nil {
+		t.Fatalf("SaveQuestion failed: %v", err)
+	}
+	if err := store.UpdateClassification(q.ID, "urgent", []string{"учёба", "сессия"}); err != nil {
+		t.Fatalf("UpdateClassification failed: %v", err)
+	}
+
+	got, err := store.GetQuestion(q.ID)
+	if err != nil {
+		t.Fatalf("GetQuestion failed: %v", err)
+	}
+	if got.Category != "urgent" || len(got.Tags) != 2 || got.Tags[1] != "сессия" {
+		t.Errorf("Unexpected classification: %q %v", got.Category, got.Tags)
+	}
+}
+
+func TestHeldQuestion(t *testing.T) {
+	store := createTestDB(t)
+
+	q := &models.Question{UserID: 1, Username: "u1", Text: "Ссылки", Held: true, Tags: []string{"реклама"}}
+	if err := store.SaveQuestion(q); err != nil {
+		t.Fatalf("SaveQuestion failed: %v", err)
+	}
+	got, _ := store.GetQuestion(q.ID)
+	if !got.Held || len(got.Tags) != 1 {
+		t.Errorf("Expected held question with tags, got %+v", got)
+	}
+
+	if err := store.SetHeld(q.ID, false); err != nil {
+		t.Fatalf("SetHeld failed: %v", err)
+	}
+	if got, _ := store.GetQuestion(q.ID); got.Held {
+		t.Errorf("Expected hold to be released")
+	}
+}
+
+func TestRejectedQuestion(t *testing.T) {
+	store := createTestDB(t)
+
+	q := &models.Question{UserID: 1, Username: "u1", Text: "Реклама"}
+	if err := store.SaveQuestion(q); err != nil {
+		t.Fatalf("SaveQuestion failed: %v", err)
+	}
+	q.Rejected = true
+	q.Answer = "не по теме"
+	if err := store.UpdateQuestion(q); err != nil {
+		t.Fatalf("UpdateQuestion failed: %v", err)
+	}
+
+	got, err := store.GetQuestion(q.ID)
+	if err != nil {
+		t.Fatalf("GetQuestion failed: %v", err)
+	}
+	if !got.Rejected || got.Answered || got.Answer != "не по теме" || got.Open() {
+		t.Errorf("Unexpected rejected question: %+v", got)
+	}
+}
+
+func TestUpdateTranslation(t *testing.T) {
+	store := createTestDB(t)
+
+	q := &models.Question{UserID: 1, Username: "u1", Text: "Коли сесія?", Language: "uk"}
+	if err := store.SaveQuestion(q); err != nil {
+		t.Fatalf("SaveQuestion failed: %v", err)
+	}
+	if err := store.UpdateTranslation(q.ID, "uk", "Когда сессия?"); err != nil {
+		t.Fatalf("UpdateTranslation failed: %v", err)
+	}
+
+	got, err := store.GetQuestion(q.ID)
+	if err != nil {
+		t.Fatalf("GetQuestion failed: %v", err)
+	}
+	if got.Language != "uk" || got.Translation != "Когда сессия?" {
+		t.Errorf("Unexpected translation: %q %q", got.Language, got.Translation)
+	}
+}
+
+func TestUserLanguage(t *testing.T) {
+	store := createTestDB(t)
+
+	if lang, err := store.GetUserLanguage(1); err != nil || lang != "" {
+		t.Fatalf("Expected no language, got %q (err=%v)", lang, err)
+	}
+	for _, want := range []string{"en", "uk"} {
+		if err := store.SetUserLanguage(1, want); err != nil {
+			t.Fatalf("SetUserLanguage failed: %v", err)
+		}
+		if lang, _ := store.GetUserLanguage(1); lang != want {
+			t.Errorf("Expected %q, got %q", want, lang)
+		}
+	}
+	if err := store.SetUserLanguage(1, ""); err != nil {
+		t.Fatalf("SetUserLanguage failed: %v", err)
+	}
+	if lang, _ := store.GetUserLanguage(1); lang != "" {
+		t.Errorf("Expected language to be reset, got %q", lang)
+	}
+}
+
+func TestLastUpdateID(t *testing.T) {
+	store := createTestDB(t)
+
+	if id, err := store.GetLastUpdateID(); err != nil || id != 0 {
+		t.Fatalf("Expected 0 before the first update, got %d (err=%v)", id, err)
+	}
+	for _, want := range []int{100, 101} {
+		if err := store.SetLastUpdateID(want); err != nil {
+			t.Fatalf("SetLastUpdateID failed: %v", err)
+		}
+		if id, _ := store.GetLastUpdateID(); id != want {
+			t.Errorf("Expected %d, got %d", want, id)
+		}
+	}
+}
+
+func TestBans(t *testing.T) {
+	store := createTestDB(t)
+
+	if banned, err := store.IsBanned(1); err != nil || banned {
+		t.Fatalf("Expected user not to be banned, got %v (err=%v)", banned, err)
+	}
+	for _, reason := range []string{"спам", "оскорбления"} {
+		if err := store.BanUser(1, reason); err != nil {
+			t.Fatalf("BanUser failed: %v", err)
+		}
+	}
+	if banned, _ := store.IsBanned(1); !banned {
+		t.Errorf("Expected user to be banned")
+	}
+	if banned, _ := store.IsBanned(2); banned {
+		t.Errorf("Expected other users not to be banned")
+	}
+
+	if removed, err := store.UnbanUser(1); err != nil || !removed {
+		t.Fatalf("Expected ban to be removed, got %v (err=%v)", removed, err)
+	}
+	if removed, _ := store.UnbanUser(1); removed {
+		t.Errorf("Expected second unban to report nothing removed")
+	}
+	if banned, _ := store.IsBanned(1); banned {
+		t.Errorf("Expected user to be unbanned")
+	}
+}
+
+func TestState(t *testing.T) {
+	store := createTestDB(t)
+
+	if value, err := store.GetState("staff"); err != nil || value != "" {
+		t.Fatalf("Expected empty state, got %q (err=%v)", value, err)
+	}
+	for _, want := range []string{"1,2", "3"} {
+		if err := store.SetState("staff", want); err != nil {
+			t.Fatalf("SetState failed: %v", err)
+		}
+		if value, _ := store.GetState("staff"); value != want {
+			t.Errorf("Expected %q, got %q", want, value)
+		}
+	}
+	// Служебные значения не пересекаются с ID последнего обновления
+	if id, _ := store.GetLastUpdateID(); id != 0 {
+		t.Errorf("Expected last update ID to stay 0, got %d", id)
+	}
+}
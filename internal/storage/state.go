@@ -0,0 +1,55 @@
+// internal/storage/state.go
+
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+)
+
+const lastUpdateIDKey = "last_update_id"
+
+// GetState возвращает служебное значение по ключу или пустую строку.
+func (s *SQLiteStorage) GetState(key string) (string, error) {
+	var value string
+	err := s.db.QueryRow(`SELECT value FROM bot_state WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return value, err
+}
+
+// SetState сохраняет служебное значение по ключу.
+func (s *SQLiteStorage) SetState(key, value string) error {
+	_, err := s.db.Exec(`
+INSERT INTO bot_state (key, value) VALUES (?, ?)
+ON CONFLICT(key) DO UPDATE SET value = excluded.value
+`, key, value)
+	return err
+}
+
+// GetLastUpdateID возвращает ID последнего обработанного обновления или 0.
+func (s *SQLiteStorage) GetLastUpdateID() (int, error) {
+	value, err := s.GetState(lastUpdateIDKey)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+// SetLastUpdateID запоминает ID последнего обработанного обновления.
+func (s *SQLiteStorage) SetLastUpdateID(id int) error {
+	return s.SetState(lastUpdateIDKey, strconv.Itoa(id))
+}
+
+// Ping выполняет простейший запрос: соединение открыто и файл базы читается.
+func (s *SQLiteStorage) Ping() error {
+	var one int
+	return s.db.QueryRow(`SELECT 1`).Scan(&one)
+}
+
+// Close закрывает соединение с базой.
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
@@ -9,4 +9,48 @@ type Storage interface {
 	GetAllQuestions() ([]*models.Question, error) // Новый метод
 	GetLastQuestionID() (int, error)
 	UpdateQuestion(question *models.Question) error
+	// CloseQuestion сохраняет ответ или отказ, только если вопрос ещё открыт;
+	// иначе возвращает ErrQuestionClosed
+	CloseQuestion(question *models.Question) error
+	UpdateClassification(id int, category string, tags []string) error
+	SetHeld(id int, held bool) error
+	UpdateTranslation(id int, language, translation string) error
+
+	// Шаблоны ответов
+	SaveTemplate(t *models.Template) error
+	GetTemplate(id int) (*models.Template, error)
+	GetTemplateByName(name string) (*models.Template, error)
+	GetAllTemplates() ([]*models.Template, error)
+	DeleteTemplate(name string) error
+
+	// Черновики ответов от нейросети
+	SaveDraft(d *models.Draft) error
+	GetDraft(id int) (*models.Draft, error)
+	DeleteDraft(id int) error
+
+	// История диалогов с нейросетью
+	AddChatMessage(m *models.ChatMessage) error
+	GetChatMessages(userID, limit int) ([]*models.ChatMessage, error)
+	ClearChat(userID int) error
+
+	// Язык, выбранный пользователем через /language; пустая строка — не выбран
+	GetUserLanguage(userID int) (string, error)
+	SetUserLanguage(userID int, language string) error
+
+	// ID последнего обработанного обновления Telegram (0 — ещё не было)
+	GetLastUpdateID() (int, error)
+	SetLastUpdateID(id int) error
+
+	// Служебные значения бота по ключу; пустая строка — значение не сохранялось
+	GetState(key string) (string, error)
+	SetState(key, value string) error
+
+	// Бан пользователей: забаненные не могут задавать вопросы и вызывать команды
+	BanUser(userID int, reason string) error
+	UnbanUser(userID int) (bool, error)
+	IsBanned(userID int) (bool, error)
+
+	// Ping проверяет, что база отвечает на запросы (для /readyz)
+	Ping() error
+	Close() error
 }
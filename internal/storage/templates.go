@@ -0,0 +1,83 @@
+// internal/storage/templates.go
+
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"telegram-anonymous-bot/internal/models"
+)
+
+// ErrTemplateNotFound возвращается, если шаблона с таким именем нет.
+var ErrTemplateNotFound = errors.New("шаблон не найден")
+
+// SaveTemplate создаёт шаблон или заменяет текст существующего с тем же именем.
+func (s *SQLiteStorage) SaveTemplate(t *models.Template) error {
+	_, err := s.db.Exec(`
+INSERT INTO templates (name, text)
+VALUES (?, ?)
+ON CONFLICT(name) DO UPDATE SET text = excluded.text
+`, t.Name, t.Text)
+	if err != nil {
+		return err
+	}
+
+	row := s.db.QueryRow(`SELECT id FROM templates WHERE name = ?`, t.Name)
+	return row.Scan(&t.ID)
+}
+
+func (s *SQLiteStorage) GetTemplate(id int) (*models.Template, error) {
+	t := &models.Template{}
+	row := s.db.QueryRow(`SELECT id, name, text FROM templates WHERE id = ?`, id)
+	if err := row.Scan(&t.ID, &t.Name, &t.Text); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *SQLiteStorage) GetTemplateByName(name string) (*models.Template, error) {
+	t := &models.Template{}
+	row := s.db.QueryRow(`SELECT id, name, text FROM templates WHERE name = ?`, name)
+	if err := row.Scan(&t.ID, &t.Name, &t.Text); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *SQLiteStorage) GetAllTemplates() ([]*models.Template, error) {
+	rows, err := s.db.Query(`SELECT id, name, text FROM templates ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*models.Template
+	for rows.Next() {
+		t := &models.Template{}
+		if err := rows.Scan(&t.ID, &t.Name, &t.Text); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (s *SQLiteStorage) DeleteTemplate(name string) error {
+	res, err := s.db.Exec(`DELETE FROM templates WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %q", ErrTemplateNotFound, name)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// internal/storage/users.go
+
+package storage
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// GetUserLanguage возвращает язык, выбранный пользователем, или пустую строку.
+func (s *SQLiteStorage) GetUserLanguage(userID int) (string, error) {
+	var language string
+	err := s.db.QueryRow(`SELECT language FROM user_settings WHERE user_id = ?`, userID).Scan(&language)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return language, err
+}
+
+// SetUserLanguage сохраняет язык пользователя; пустая строка сбрасывает выбор.
+func (s *SQLiteStorage) SetUserLanguage(userID int, language string) error {
+	if language == "" {
+		_, err := s.db.Exec(`DELETE FROM user_settings WHERE user_id = ?`, userID)
+		return err
+	}
+	_, err := s.db.Exec(`
+INSERT INTO user_settings (user_id, language) VALUES (?, ?)
+ON CONFLICT(user_id) DO UPDATE SET language = excluded.language
+`, userID, language)
+	return err
+}
@@ -0,0 +1,175 @@
+// Package translate переводит вопросы на язык администраторов и ответы — на язык автора вопроса.
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"telegram-anonymous-bot/internal/llm"
+)
+
+// Result — итог перевода. Text пустой, если исходный текст уже на целевом языке.
+type Result struct {
+	// Language — определённый язык исходного текста (код ISO 639-1), пусто — не удалось определить.
+	Language string
+	Text     string
+}
+
+// Translator переводит текст на язык target (код ISO 639-1: ru, uk, en).
+type Translator interface {
+	Translate(ctx context.Context, text, target string) (Result, error)
+}
+
+// New создаёт переводчик по имени из конфигурации: llm (через нейросеть) или fake (для тестов).
+func New(name string, provider llm.Provider) (Translator, error) {
+	switch strings.ToLower(name) {
+	case "", "llm":
+		if provider == nil {
+			return nil, fmt.Errorf("для перевода через нейросеть нужен LLM-провайдер")
+		}
+		return &LLM{Provider: provider}, nil
+	case "fake":
+		return &Fake{}, nil
+	default:
+		return nil, fmt.Errorf("неизвестный переводчик %q (ожидается llm или fake)", name)
+	}
+}
+
+// NormalizeLanguage приводит код языка клиента Telegram ("uk-UA", "EN") к виду ISO 639-1.
+func NormalizeLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	return code
+}
+
+// Detect грубо определяет язык по буквам: ru, uk или en. Для кириллицы без характерных букв
+// (или смешанного текста) возвращает пустую строку — язык не определён.
+func Detect(text string) string {
+	var latin, cyrillic, russian, ukrainian int
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case strings.ContainsRune("ыэъё", r):
+			russian++
+			cyrillic++
+		case strings.ContainsRune("іїєґ", r):
+			ukrainian++
+			cyrillic++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			latin++
+		}
+	}
+
+	switch {
+	case cyrillic == 0 && latin > 0:
+		return "en"
+	case latin > cyrillic:
+		return ""
+	case ukrainian > 0 && russian == 0:
+		return "uk"
+	case russian > 0 && ukrainian == 0:
+		return "ru"
+	default:
+		return ""
+	}
+}
+
+var languageNames = map[string]string{
+	"ru": "русский",
+	"uk": "украинский",
+	"en": "английский",
+}
+
+// LanguageName возвращает название языка по коду для запросов к нейросети; неизвестный
+// код возвращается как есть.
+func LanguageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// LLM переводит текст настроенной нейросетью.
+type LLM struct {
+	Provider llm.Provider
+}
+
+func (t *LLM) Translate(ctx context.Context, text, target string) (Result, error) {
+	// Очевидно тот же язык — нейросеть не вызываем
+	if lang := Detect(text); lang == target {
+		return Result{Language: lang}, nil
+	}
+
+	reply, err := t.Provider.Generate(ctx, Prompt(text, target))
+	if err != nil {
+		return Result{}, err
+	}
+	return Parse(reply, target)
+}
+
+// Prompt формирует запрос на определение языка и перевод текста.
+func Prompt(text, target string) string {
+	return fmt.Sprintf(`Определи язык текста и переведи его на %s язык (код %s).
+Сохрани смысл, тон и форматирование, ничего не добавляй от себя.
+Если текст уже на этом языке, верни пустой перевод.
+Ответь только JSON без пояснений: {"language": "<код ISO 639-1>", "translation": "..."}
+
+Текст:
+%s`, LanguageName(target), target, text)
+}
+
+// Parse разбирает ответ нейросети; лишний текст вокруг JSON игнорируется.
+func Parse(reply, target string) (Result, error) {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return Result{}, fmt.Errorf("в ответе нейросети нет JSON: %q", reply)
+	}
+
+	var raw struct {
+		Language    string `json:"language"`
+		Translation string `json:"translation"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &raw); err != nil {
+		return Result{}, fmt.Errorf("ошибка обработки ответа нейросети: %w", err)
+	}
+
+	res := Result{Language: NormalizeLanguage(raw.Language), Text: strings.TrimSpace(raw.Translation)}
+	if res.Language == target {
+		res.Text = ""
+	}
+	return res, nil
+}
+
+// Fake — детерминированный переводчик для тестов: язык определяет по буквам,
+// а «перевод» помечает целевым языком: "[en] текст".
+type Fake struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *Fake) Translate(ctx context.Context, text, target string) (Result, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, target+": "+text)
+	f.mu.Unlock()
+
+	lang := Detect(text)
+	if lang == target {
+		return Result{Language: lang}, nil
+	}
+	return Result{Language: lang, Text: "[" + target + "] " + text}, nil
+}
+
+// Calls возвращает все запросы на перевод в виде "<язык>: <текст>".
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
@@ -0,0 +1,67 @@
+package translate_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"telegram-anonymous-bot/internal/llm"
+	"telegram-anonymous-bot/internal/translate"
+)
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		"Когда будет сессия? Объясните": "ru",
+		"Коли буде сесія? Дякую їм":     "uk",
+		"When is the exam?":                "en",
+		"Когда сессия":                     "", // нет характерных букв
+		"":                                 "",
+		"Привет, how are you doing today?": "",
+	}
+	for text, want := range tests {
+		if got := translate.Detect(text); got != want {
+			t.Errorf("Detect(%q) = %q, expected %q", text, got, want)
+		}
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	for in, want := range map[string]string{"uk-UA": "uk", "EN": "en", "pt_br": "pt", "": ""} {
+		if got := translate.NormalizeLanguage(in); got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, expected %q", in, got, want)
+		}
+	}
+}
+
+func TestLLMTranslate(t *testing.T) {
+	provider := &llm.Fake{Responses: []string{"Вот перевод: {\"language\": \"uk\", \"translation\": \"Когда сессия?\"}"}}
+	tr := &translate.LLM{Provider: provider}
+
+	res, err := tr.Translate(context.Background(), "Коли сесія?", "ru")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if res.Language != "uk" || res.Text != "Когда сессия?" {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if prompts := provider.Prompts(); len(prompts) != 1 || !strings.Contains(prompts[0], "русский") {
+		t.Errorf("Expected prompt with target language, got %v", prompts)
+	}
+
+	// Текст уже на целевом языке — нейросеть не вызывается
+	if res, _ := tr.Translate(context.Background(), "Объясните, пожалуйста", "ru"); res.Language != "ru" || res.Text != "" {
+		t.Errorf("Expected no translation for Russian text, got %+v", res)
+	}
+	if n := len(provider.Prompts()); n != 1 {
+		t.Errorf("Expected no extra LLM calls, got %d", n)
+	}
+}
+
+func TestParse(t *testing.T) {
+	if res, err := translate.Parse(`{"language":"RU","translation":"тот же текст"}`, "ru"); err != nil || res.Text != "" {
+		t.Errorf("Expected empty translation for same language, got %+v (err=%v)", res, err)
+	}
+	if _, err := translate.Parse("не JSON", "ru"); err == nil {
+		t.Errorf("Expected error for reply without JSON")
+	}
+}
@@ -0,0 +1,130 @@
+// Package updatelog записывает входящие обновления Telegram в файл JSONL (одно обновление
+// на строку) и читает такие файлы — чтобы воспроизвести ошибку на трафике с продакшена
+// или собрать регрессионный тест.
+package updatelog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Redacted — чем заменяются скрытые значения.
+const Redacted = "[redacted]"
+
+// personalFields — поля с личными данными, которые скрываются в любом месте обновления.
+var personalFields = map[string]bool{
+	"first_name":   true,
+	"last_name":    true,
+	"username":     true,
+	"phone_number": true,
+	"bio":          true,
+}
+
+// contentFields — тексты сообщений. Команды сохраняются как есть: без них запись не воспроизвести.
+var contentFields = map[string]bool{
+	"text":    true,
+	"caption": true,
+}
+
+// Recorder дописывает обновления в файл. Безопасен для одновременного использования.
+type Recorder struct {
+	redact bool
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Open открывает файл path на дозапись, создавая его при необходимости. Если redact,
+// в записи скрываются имена, username и телефоны, а также тексты сообщений, кроме команд;
+// ID пользователей и чатов остаются — по ним определяются роли и порядок обработки.
+func Open(path string, redact bool) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть журнал обновлений: %w", err)
+	}
+	return &Recorder{file: file, redact: redact}, nil
+}
+
+// Record дописывает обновление одной строкой.
+func (r *Recorder) Record(update tgbotapi.Update) error {
+	line, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	if r.redact {
+		if line, err = Redact(line); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close закрывает файл.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Redact скрывает в JSON обновления личные данные и тексты сообщений, кроме команд.
+func Redact(update []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(update, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redact(v))
+}
+
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			s, isString := value.(string)
+			switch {
+			case isString && personalFields[key]:
+				v[key] = Redacted
+			case isString && contentFields[key] && !strings.HasPrefix(s, "/"):
+				v[key] = Redacted
+			default:
+				v[key] = redact(value)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+	return v
+}
+
+// Read читает обновления из JSONL и передаёт их fn по порядку. Пустые строки пропускаются;
+// при ошибке разбора возвращается номер строки.
+func Read(r io.Reader, fn func(update tgbotapi.Update) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var update tgbotapi.Update
+		if err := json.Unmarshal([]byte(line), &update); err != nil {
+			return fmt.Errorf("строка %d: %w", n, err)
+		}
+		if err := fn(update); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,66 @@
+package updatelog_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"telegram-anonymous-bot/internal/updatelog"
+)
+
+func update(id int, text string) tgbotapi.Update {
+	return tgbotapi.Update{UpdateID: id, Message: &tgbotapi.Message{
+		MessageID: id,
+		From:      &tgbotapi.User{ID: 12345, FirstName: "Анна", UserName: "anna"},
+		Chat:      &tgbotapi.Chat{ID: 12345, Type: "private"},
+		Text:      text,
+	}}
+}
+
+func TestRecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "updates.jsonl")
+	for _, redact := range []bool{false, true} {
+		rec, err := updatelog.Open(path, redact)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		rec.Record(update(1, "Когда сессия?"))
+		rec.Record(update(2, "/answer 1 Завтра"))
+		if err := rec.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+
+	raw, _ := os.ReadFile(path)
+	if strings.Count(string(raw), "\n") != 4 {
+		t.Fatalf("Expected 4 lines appended, got:\n%s", raw)
+	}
+
+	var got []tgbotapi.Update
+	err := updatelog.Read(strings.NewReader(string(raw)+"\n"), func(u tgbotapi.Update) error {
+		got = append(got, u)
+		return nil
+	})
+	if err != nil || len(got) != 4 {
+		t.Fatalf("Expected 4 updates, got %d (%v)", len(got), err)
+	}
+	if got[0].Message.Text != "Когда сессия?" || got[0].Message.From.UserName != "anna" {
+		t.Errorf("Unredacted record must keep the update as is, got %+v", got[0].Message)
+	}
+	redacted := got[2].Message
+	if redacted.Text != updatelog.Redacted || redacted.From.FirstName != updatelog.Redacted || redacted.From.UserName != updatelog.Redacted {
+		t.Errorf("Expected personal data and question text to be redacted, got %+v %+v", redacted, redacted.From)
+	}
+	if redacted.From.ID != 12345 || got[3].Message.Text != "/answer 1 Завтра" {
+		t.Errorf("IDs and commands must be kept for replay, got %+v, %q", redacted.From, got[3].Message.Text)
+	}
+}
+
+func TestReadReportsLine(t *testing.T) {
+	err := updatelog.Read(strings.NewReader(`{"update_id":1}`+"\nnot json\n"), func(tgbotapi.Update) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "строка 2") {
+		t.Errorf("Expected error with line number, got %v", err)
+	}
+}
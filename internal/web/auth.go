@@ -0,0 +1,295 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/pkg/logger"
+)
+
+// sessionCookie — cookie вошедшего администратора: "<id>.<истекает, unix>.<эпоха>.<подпись>".
+// Подпись ключом из токена бота переживает перезапуск, а права администратора проверяются
+// при каждом запросе. Эпоха хранится в базе (sessionEpochKey): выход и отзыв прав меняют её,
+// и все выданные пользователю cookie перестают действовать.
+const sessionCookie = "tgbot_session"
+
+// sessionEpochKey возвращает ключ bot_state с эпохой сессий пользователя.
+func sessionEpochKey(userID int64) string {
+	return "web_session_epoch:" + strconv.FormatInt(userID, 10)
+}
+
+// Сроки по умолчанию, если WEB_SESSION_TTL или WEB_LOGIN_TTL равны нулю.
+const (
+	defaultSessionTTL = 12 * time.Hour
+	defaultLoginTTL   = 10 * time.Minute
+)
+
+// login — выданная командой /web одноразовая ссылка входа.
+type login struct {
+	userID  int64
+	expires time.Time
+}
+
+func sessionKey(botToken string) []byte {
+	mac := hmac.New(sha256.New, []byte(botToken))
+	mac.Write([]byte("web-session"))
+	return mac.Sum(nil)
+}
+
+func (s *Server) sign(value string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func equalTokens(got, want string) bool {
+	return got != "" && hmac.Equal([]byte(got), []byte(want))
+}
+
+func (s *Server) sessionTTL() time.Duration {
+	if ttl := s.core.Config.WebSessionTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultSessionTTL
+}
+
+func (s *Server) loginTTL() time.Duration {
+	if ttl := s.core.Config.WebLoginTTL; ttl > 0 {
+		return ttl
+	}
+	return defaultLoginTTL
+}
+
+// session возвращает cookie сессии и ID вошедшего пользователя; ok = false, если
+// cookie нет, она подделана, истекла или отозвана.
+func (s *Server) session(r *http.Request) (cookie string, userID int64, ok bool) {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", 0, false
+	}
+	parts := strings.Split(c.Value, ".")
+	if len(parts) != 4 || !equalTokens(parts[3], s.sign(strings.Join(parts[:3], "."))) {
+		return "", 0, false
+	}
+	userID, err1 := strconv.ParseInt(parts[0], 10, 64)
+	expires, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil || time.Now().Unix() >= expires {
+		return "", 0, false
+	}
+	epoch, err := s.sessionEpoch(userID)
+	if err != nil {
+		core.LogStorageError(r.Context(), "GetState", err)
+		return "", 0, false
+	}
+	if !equalTokens(parts[2], epoch) {
+		return "", 0, false
+	}
+	return c.Value, userID, true
+}
+
+// sessionEpoch возвращает текущую эпоху сессий пользователя ("0", пока их не отзывали).
+func (s *Server) sessionEpoch(userID int64) (string, error) {
+	epoch, err := s.core.Storage.GetState(sessionEpochKey(userID))
+	if epoch == "" {
+		epoch = "0"
+	}
+	return epoch, err
+}
+
+// revokeSessions меняет эпоху сессий пользователя: все выданные ему cookie перестают
+// действовать. Эпоха случайная, чтобы одновременные выходы не вернули старую.
+func (s *Server) revokeSessions(ctx context.Context, userID int64) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		slog.ErrorContext(ctx, "Сессии веб-панели не отозваны", "error", err)
+		return
+	}
+	if err := s.core.Storage.SetState(sessionEpochKey(userID), hex.EncodeToString(buf)); err != nil {
+		core.LogStorageError(ctx, "SetState", err)
+	}
+}
+
+// startSession выдаёт администратору userID cookie сессии текущей эпохи.
+func (s *Server) startSession(w http.ResponseWriter, r *http.Request, userID int64) bool {
+	epoch, err := s.sessionEpoch(userID)
+	if err != nil {
+		core.LogStorageError(r.Context(), "GetState", err)
+		return false
+	}
+	ttl := s.sessionTTL()
+	payload := fmt.Sprintf("%d.%d.%s", userID, time.Now().Add(ttl).Unix(), epoch)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    payload + "." + s.sign(payload),
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(s.core.Config.WebURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return true
+}
+
+// csrfToken — токен форм, привязанный к сессии.
+func (s *Server) csrfToken(cookie string) string {
+	return s.sign("csrf:" + cookie)[:32]
+}
+
+// LoginLink выдаёт администратору userID одноразовую ссылку входа в панель (команда /web).
+func (s *Server) LoginLink(userID int64) (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	now := time.Now()
+	s.mu.Lock()
+	for t, l := range s.logins {
+		if now.After(l.expires) {
+			delete(s.logins, t)
+		}
+	}
+	s.logins[token] = login{userID: userID, expires: now.Add(s.loginTTL())}
+	s.mu.Unlock()
+
+	return s.core.Config.WebURL + "/login/link?" + url.Values{"token": {token}}.Encode(), nil
+}
+
+// LoginTTL возвращает, сколько действует ссылка из LoginLink.
+func (s *Server) LoginTTL() time.Duration {
+	return s.loginTTL()
+}
+
+// loginPage показывает Login Widget и подсказку про /web. Вошедших отправляет к списку.
+func (s *Server) loginPage(w http.ResponseWriter, r *http.Request) {
+	if _, userID, ok := s.session(r); ok && s.core.Role(userID) >= core.RoleAdmin {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	s.render(w, http.StatusOK, "login", page{
+		Lang:    s.core.DefaultLang(),
+		BotName: s.botName,
+		AuthURL: s.core.Config.WebURL + "/login/telegram",
+	})
+}
+
+// loginWidget принимает данные Telegram Login Widget.
+func (s *Server) loginWidget(w http.ResponseWriter, r *http.Request) {
+	userID, err := verifyWidget(s.core.Config.TelegramBotToken, r.URL.Query(), s.loginTTL(), time.Now())
+	if err != nil {
+		slog.Warn("Вход в веб-панель отклонён", "method", "widget", "error", err)
+		s.loginFailed(w, "web.login.expired")
+		return
+	}
+	s.admit(w, r, userID, "widget")
+}
+
+// linkPage просит подтвердить вход по ссылке из /web. Ссылка расходуется только кнопкой
+// (POST): предпросмотр ссылок в Telegram и браузере не должен её израсходовать.
+func (s *Server) linkPage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	s.mu.Lock()
+	l, ok := s.logins[token]
+	s.mu.Unlock()
+	if !ok || time.Now().After(l.expires) {
+		s.loginFailed(w, "web.login.expired")
+		return
+	}
+	s.render(w, http.StatusOK, "link", page{Lang: s.core.DefaultLang(), Token: token})
+}
+
+// loginLink расходует одноразовую ссылку и выдаёт сессию.
+func (s *Server) loginLink(w http.ResponseWriter, r *http.Request) {
+	token := r.PostFormValue("token")
+	s.mu.Lock()
+	l, ok := s.logins[token]
+	delete(s.logins, token)
+	s.mu.Unlock()
+	if !ok || time.Now().After(l.expires) {
+		slog.Warn("Вход в веб-панель отклонён", "method", "link", "error", "ссылка недействительна или устарела")
+		s.loginFailed(w, "web.login.expired")
+		return
+	}
+	s.admit(w, r, l.userID, "link")
+}
+
+// admit пускает в панель только администраторов.
+func (s *Server) admit(w http.ResponseWriter, r *http.Request, userID int64, method string) {
+	if s.core.Role(userID) < core.RoleAdmin {
+		slog.Warn("Вход в веб-панель отклонён", "method", method, "user", logger.ID(userID), "error", "не администратор")
+		s.loginFailed(w, "web.login.denied")
+		return
+	}
+	if !s.startSession(w, r, userID) {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	slog.Info("Вход в веб-панель", "method", method, "staff", logger.ID(userID))
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *Server) loginFailed(w http.ResponseWriter, key string) {
+	lang := s.core.DefaultLang()
+	s.render(w, http.StatusForbidden, "login", page{
+		Lang:    lang,
+		Error:   s.core.T(lang, key),
+		BotName: s.botName,
+		AuthURL: s.core.Config.WebURL + "/login/telegram",
+	})
+}
+
+// logout завершает все сессии администратора, а не только в этом браузере: без этого
+// скопированная cookie оставалась бы действительной до истечения срока.
+func (s *Server) logout(w http.ResponseWriter, r *http.Request, v visit) {
+	s.revokeSessions(v.ctx, v.userID)
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1, HttpOnly: true})
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// verifyWidget проверяет подпись данных Telegram Login Widget и возвращает ID пользователя.
+// Подпись — HMAC-SHA256 отсортированных "ключ=значение" через \n с ключом SHA256(токен бота);
+// данные старше maxAge не принимаются.
+// См. https://core.telegram.org/widgets/login#checking-authorization
+func verifyWidget(botToken string, values url.Values, maxAge time.Duration, now time.Time) (int64, error) {
+	hash := values.Get("hash")
+	if hash == "" {
+		return 0, errors.New("нет подписи")
+	}
+	var fields []string
+	for key := range values {
+		if key != "hash" {
+			fields = append(fields, key+"="+values.Get(key))
+		}
+	}
+	sort.Strings(fields)
+
+	secret := sha256.Sum256([]byte(botToken))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(fields, "\n")))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(strings.ToLower(hash))) {
+		return 0, errors.New("неверная подпись")
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return 0, errors.New("нет auth_date")
+	}
+	if maxAge > 0 && now.Sub(time.Unix(authDate, 0)) > maxAge {
+		return 0, errors.New("данные входа устарели")
+	}
+	return strconv.ParseInt(values.Get("id"), 10, 64)
+}
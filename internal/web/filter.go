@@ -0,0 +1,142 @@
+package web
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/moderation"
+)
+
+// PageSize — сколько вопросов показывать на одной странице списка.
+const PageSize = 50
+
+// Состояния вопроса в фильтре списка.
+const (
+	StatusOpen     = "open"     // ждёт ответа (в том числе задержанные фильтром)
+	StatusHeld     = "held"     // задержан фильтром и ждёт проверки
+	StatusAnswered = "answered" // отвечен
+	StatusRejected = "rejected" // отклонён
+	StatusAll      = "all"
+)
+
+// Statuses и Categories — значения фильтров в порядке вывода.
+var (
+	Statuses   = []string{StatusOpen, StatusHeld, StatusAnswered, StatusRejected, StatusAll}
+	Categories = []string{moderation.CategoryUrgent, moderation.CategoryNormal, moderation.CategorySpam, moderation.CategoryAbusive}
+)
+
+// Filter — фильтр списка вопросов из параметров запроса.
+type Filter struct {
+	Status   string
+	Category string // пусто — любая
+	Tag      string // пусто — любая
+	Search   string // подстрока текста, перевода или ответа без учёта регистра
+	Page     int    // с 1
+}
+
+// ParseFilter читает фильтр из параметров запроса. По умолчанию показываются
+// вопросы, ждущие ответа.
+func ParseFilter(values url.Values) Filter {
+	f := Filter{
+		Status:   values.Get("status"),
+		Category: values.Get("category"),
+		Tag:      strings.ToLower(strings.TrimLeft(strings.TrimSpace(values.Get("tag")), "#")),
+		Search:   strings.TrimSpace(values.Get("q")),
+	}
+	if !slices.Contains(Statuses, f.Status) {
+		f.Status = StatusOpen
+	}
+	if !slices.Contains(Categories, f.Category) {
+		f.Category = ""
+	}
+	f.Page, _ = strconv.Atoi(values.Get("page"))
+	f.Page = max(f.Page, 1)
+	return f
+}
+
+// Query возвращает параметры запроса для фильтра (для ссылок на страницы).
+func (f Filter) Query() string {
+	values := url.Values{"status": {f.Status}}
+	if f.Category != "" {
+		values.Set("category", f.Category)
+	}
+	if f.Tag != "" {
+		values.Set("tag", f.Tag)
+	}
+	if f.Search != "" {
+		values.Set("q", f.Search)
+	}
+	if f.Page > 1 {
+		values.Set("page", strconv.Itoa(f.Page))
+	}
+	return values.Encode()
+}
+
+// WithPage возвращает тот же фильтр для страницы page.
+func (f Filter) WithPage(page int) Filter {
+	f.Page = page
+	return f
+}
+
+// Match сообщает, подходит ли вопрос под фильтр.
+func (f Filter) Match(q *models.Question) bool {
+	switch f.Status {
+	case StatusOpen:
+		if !q.Open() {
+			return false
+		}
+	case StatusHeld:
+		if !q.Open() || !q.Held {
+			return false
+		}
+	case StatusAnswered:
+		if !q.Answered {
+			return false
+		}
+	case StatusRejected:
+		if !q.Rejected {
+			return false
+		}
+	}
+	if f.Category != "" && q.Category != f.Category &&
+		!(f.Category == moderation.CategoryNormal && q.Category == "") {
+		return false
+	}
+	if f.Tag != "" && !slices.Contains(q.Tags, f.Tag) {
+		return false
+	}
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(q.Text), search) &&
+			!strings.Contains(strings.ToLower(q.Translation), search) &&
+			!strings.Contains(strings.ToLower(q.Answer), search) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply отбирает вопросы по фильтру и возвращает страницу f.Page и общее число
+// подходящих. Срочные вопросы идут первыми, спам и оскорбления — последними,
+// внутри категории — сначала новые.
+func (f Filter) Apply(questions []*models.Question) ([]*models.Question, int) {
+	var matched []*models.Question
+	for _, q := range questions {
+		if f.Match(q) {
+			matched = append(matched, q)
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b *models.Question) int {
+		if pa, pb := moderation.Priority(a.Category), moderation.Priority(b.Category); pa != pb {
+			return pa - pb
+		}
+		return b.ID - a.ID
+	})
+
+	start := min((f.Page-1)*PageSize, len(matched))
+	end := min(start+PageSize, len(matched))
+	return matched[start:end], len(matched)
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-anonymous-bot/internal/bot/core"
+)
+
+// mediaTypes — Content-Type вложения, если Telegram его не сообщил.
+var mediaTypes = map[string]string{
+	"photo": "image/jpeg",
+	"video": "video/mp4",
+}
+
+// media отдаёт вложение вопроса: получает путь через getFile и скачивает файл с серверов
+// Telegram. Токен бота в адресе файла не покидает сервер.
+func (s *Server) media(w http.ResponseWriter, r *http.Request, v visit) {
+	q, ok := s.loadQuestion(w, r, v)
+	if !ok {
+		return
+	}
+	if q.FileID == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	resp, err := s.core.Messenger.Request(tgbotapi.FileConfig{FileID: q.FileID})
+	if err != nil {
+		slog.ErrorContext(v.ctx, "Не удалось получить файл вопроса", "question", q.ID, "error", core.WithoutURL(err))
+		http.Error(w, "getFile failed", http.StatusBadGateway)
+		return
+	}
+	var file tgbotapi.File
+	if err := json.Unmarshal(resp.Result, &file); err != nil || file.FilePath == "" {
+		slog.ErrorContext(v.ctx, "Telegram не вернул путь к файлу", "question", q.ID, "error", err)
+		http.Error(w, "getFile failed", http.StatusBadGateway)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, s.fileURL(file.FilePath), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	client := s.core.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		slog.ErrorContext(v.ctx, "Не удалось скачать файл вопроса", "question", q.ID, "error", core.WithoutURL(err))
+		http.Error(w, "download failed", http.StatusBadGateway)
+		return
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		slog.ErrorContext(v.ctx, "Не удалось скачать файл вопроса", "question", q.ID, "status", res.StatusCode)
+		http.Error(w, "download failed", http.StatusBadGateway)
+		return
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		if t, ok := mediaTypes[q.MediaType]; ok {
+			contentType = t
+		}
+	}
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if res.ContentLength >= 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(res.ContentLength))
+	}
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	if _, err := io.Copy(w, res.Body); err != nil {
+		slog.WarnContext(v.ctx, "Файл вопроса передан не полностью", "question", q.ID, "error", core.WithoutURL(err))
+	}
+}
+
+// fileURL возвращает адрес скачивания файла. Для своего TELEGRAM_API_ENDPOINT
+// (https://host/bot%s/%s) файлы лежат рядом: https://host/file/bot%s/%s.
+func (s *Server) fileURL(path string) string {
+	endpoint := tgbotapi.FileEndpoint
+	if custom := s.core.Config.TelegramAPIEndpoint; strings.Contains(custom, "/bot%s/") {
+		endpoint = strings.Replace(custom, "/bot%s/", "/file/bot%s/", 1)
+	}
+	return fmt.Sprintf(endpoint, s.core.Config.TelegramBotToken, path)
+}
@@ -0,0 +1,357 @@
+// Package web — веб-панель администраторов: список вопросов с фильтрами, просмотр вложений
+// и ответ, отклонение и темы вопроса. Действия выполняются теми же методами BotCore, что и
+// команды бота (/answer, /reject, /tag), поэтому автор получает те же уведомления.
+//
+// Вход — через Telegram Login Widget или по одноразовой ссылке из команды /web; в панель
+// пускаются только администраторы бота.
+package web
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/bot/handlers"
+	"telegram-anonymous-bot/internal/i18n"
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/pkg/logger"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+// pageNames — страницы панели; каждая собирается из layout.html и <имя>.html.
+var pageNames = []string{"list", "question", "login", "link"}
+
+// Server — веб-панель. Безопасен для одновременного использования.
+type Server struct {
+	core *core.BotCore
+	// botName — имя бота для Login Widget; пусто — виджет не показывается.
+	botName string
+	// key подписывает cookie сессий и CSRF-токены.
+	key   []byte
+	pages map[string]*template.Template
+	mux   *http.ServeMux
+
+	mu     sync.Mutex
+	logins map[string]login
+}
+
+// New собирает панель поверх bc. Адрес панели и сроки входа берутся из bc.Config.
+func New(bc *core.BotCore, botName string) *Server {
+	s := &Server{
+		core:    bc,
+		botName: botName,
+		key:     sessionKey(bc.Config.TelegramBotToken),
+		pages:   make(map[string]*template.Template),
+		logins:  make(map[string]login),
+	}
+	for _, name := range pageNames {
+		s.pages[name] = template.Must(template.New("layout.html").Funcs(templateFuncs).
+			ParseFS(templateFiles, "templates/layout.html", "templates/"+name+".html"))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.staff(s.list))
+	mux.HandleFunc("GET /questions/{id}", s.staff(s.question))
+	mux.HandleFunc("GET /questions/{id}/media", s.staff(s.media))
+	mux.HandleFunc("POST /questions/{id}/answer", s.staff(s.answer))
+	mux.HandleFunc("POST /questions/{id}/reject", s.staff(s.reject))
+	mux.HandleFunc("POST /questions/{id}/tags", s.staff(s.tags))
+	mux.HandleFunc("GET /login", s.loginPage)
+	mux.HandleFunc("GET /login/telegram", s.loginWidget)
+	mux.HandleFunc("GET /login/link", s.linkPage)
+	mux.HandleFunc("POST /login/link", s.loginLink)
+	mux.HandleFunc("POST /logout", s.staff(s.logout))
+	s.mux = mux
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "same-origin")
+	w.Header().Set("Cache-Control", "no-store")
+	s.mux.ServeHTTP(w, r)
+}
+
+// page — данные шаблонов; каждая страница использует свою часть полей.
+type page struct {
+	Lang string
+	// CSRF — токен для форм; пусто — посетитель не вошёл.
+	CSRF   string
+	Notice string
+	Error  string
+
+	// list
+	Filter    Filter
+	Questions []*models.Question
+	Total     int
+	Prev      string // ссылки на соседние страницы; пусто — страницы нет
+	Next      string
+
+	// question
+	Question  *models.Question
+	Templates []*models.Template
+
+	// login, link
+	BotName string
+	AuthURL string
+	Token   string
+}
+
+// templateFuncs — функции шаблонов. "t" подменяется при выводе переводом на язык страницы.
+var templateFuncs = template.FuncMap{
+	"t":          func(key string, _ ...any) string { return key },
+	"n":          func(key string, n int) string { return key },
+	"status":     statusKey,
+	"css":        func(key string) string { return strings.ReplaceAll(key, ".", "-") },
+	"statuses":   func() []string { return Statuses },
+	"categories": func() []string { return Categories },
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "—"
+		}
+		return t.Format("02.01.2006 15:04")
+	},
+	"excerpt": func(s string) string {
+		if r := []rune(s); len(r) > 120 {
+			return string(r[:120]) + "…"
+		}
+		return s
+	},
+	"join": strings.Join,
+}
+
+// render выводит страницу name на языке p.Lang.
+func (s *Server) render(w http.ResponseWriter, status int, name string, p page) {
+	tmpl, err := s.pages[name].Clone()
+	if err == nil {
+		tmpl.Funcs(template.FuncMap{
+			"t": func(key string, kv ...any) string {
+				params := make(i18n.Params, len(kv)/2)
+				for i := 0; i+1 < len(kv); i += 2 {
+					params[fmt.Sprint(kv[i])] = kv[i+1]
+				}
+				return s.core.T(p.Lang, key, params)
+			},
+			"n": func(key string, n int) string { return s.core.N(p.Lang, key, n) },
+		})
+		var out strings.Builder
+		if err = tmpl.Execute(&out, p); err == nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(out.String()))
+			return
+		}
+	}
+	slog.Error("Ошибка вывода страницы веб-панели", "page", name, "error", err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// visit — запрос вошедшего администратора.
+type visit struct {
+	ctx    context.Context
+	userID int64
+	lang   string
+	csrf   string
+}
+
+func (v visit) page() page {
+	return page{Lang: v.lang, CSRF: v.csrf}
+}
+
+// staff пропускает только вошедших администраторов, а у POST-запросов ещё и проверяет
+// CSRF-токен. Остальных отправляет на страницу входа. Если у вошедшего отозвали права,
+// его сессии отзываются: cookie не оживёт, даже если права вернут.
+func (s *Server) staff(next func(http.ResponseWriter, *http.Request, visit)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, userID, ok := s.session(r)
+		if ok && s.core.Role(userID) < core.RoleAdmin {
+			slog.Warn("Сессия веб-панели отозвана: пользователь больше не администратор", "user", logger.ID(userID))
+			s.revokeSessions(r.Context(), userID)
+			ok = false
+		}
+		if !ok {
+			if r.Method == http.MethodGet {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		v := visit{
+			ctx:    logger.With(r.Context(), "staff", logger.ID(userID)),
+			userID: userID,
+			csrf:   s.csrfToken(cookie),
+		}
+		if r.Method == http.MethodPost && !equalTokens(r.PostFormValue("csrf"), v.csrf) {
+			http.Error(w, "bad csrf token", http.StatusForbidden)
+			return
+		}
+		v.lang = s.core.UserLang(v.ctx, userID, "")
+		next(w, r, v)
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, v visit) {
+	questions, err := s.core.Storage.GetAllQuestions()
+	if err != nil {
+		core.LogStorageError(v.ctx, "GetAllQuestions", err)
+		p := v.page()
+		p.Error = s.core.T(v.lang, "common.list_failed", i18n.Params{"error": err})
+		s.render(w, http.StatusInternalServerError, "list", p)
+		return
+	}
+
+	p := v.page()
+	p.Filter = ParseFilter(r.URL.Query())
+	p.Questions, p.Total = p.Filter.Apply(questions)
+	if p.Filter.Page > 1 {
+		p.Prev = "/?" + p.Filter.WithPage(p.Filter.Page-1).Query()
+	}
+	if p.Filter.Page*PageSize < p.Total {
+		p.Next = "/?" + p.Filter.WithPage(p.Filter.Page+1).Query()
+	}
+	s.render(w, http.StatusOK, "list", p)
+}
+
+// doneNotices — ключи сообщений после успешного действия (?done=...).
+var doneNotices = map[string]string{
+	"answer": "web.done.answer",
+	"reject": "web.done.reject",
+	"tags":   "web.done.tags",
+}
+
+func (s *Server) question(w http.ResponseWriter, r *http.Request, v visit) {
+	q, ok := s.loadQuestion(w, r, v)
+	if !ok {
+		return
+	}
+	p := v.page()
+	if key, ok := doneNotices[r.URL.Query().Get("done")]; ok {
+		p.Notice = s.core.T(v.lang, key)
+	}
+	s.showQuestion(w, http.StatusOK, v, q, p)
+}
+
+// showQuestion выводит страницу вопроса со списком шаблонов ответа.
+func (s *Server) showQuestion(w http.ResponseWriter, status int, v visit, q *models.Question, p page) {
+	p.Question = q
+	if q.Open() {
+		templates, err := s.core.Storage.GetAllTemplates()
+		if err != nil {
+			core.LogStorageError(v.ctx, "GetAllTemplates", err)
+		}
+		p.Templates = templates
+	}
+	s.render(w, status, "question", p)
+}
+
+// loadQuestion читает вопрос из пути запроса; если его нет, отвечает 404.
+func (s *Server) loadQuestion(w http.ResponseWriter, r *http.Request, v visit) (*models.Question, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	q, err := s.core.Storage.GetQuestion(id)
+	if err != nil {
+		slog.InfoContext(v.ctx, "Вопрос для веб-панели не найден", "question", id, "error", err)
+		http.NotFound(w, r)
+		return nil, false
+	}
+	return q, true
+}
+
+func (s *Server) answer(w http.ResponseWriter, r *http.Request, v visit) {
+	q, ok := s.loadQuestion(w, r, v)
+	if !ok {
+		return
+	}
+
+	answerText := strings.TrimSpace(r.PostFormValue("answer"))
+	if answerText == "" && r.PostFormValue("template") != "" {
+		tID, err := strconv.Atoi(r.PostFormValue("template"))
+		if err != nil {
+			http.Error(w, "bad template", http.StatusBadRequest)
+			return
+		}
+		t, err := s.core.Storage.GetTemplate(tID)
+		if err != nil {
+			s.failed(w, v, q, s.core.T(v.lang, "template.not_found", i18n.Params{"error": err}))
+			return
+		}
+		if answerText, err = handlers.RenderTemplate(t, q, time.Now()); err != nil {
+			s.failed(w, v, q, s.core.T(v.lang, "template.invalid", i18n.Params{"name": t.Name, "error": err}))
+			return
+		}
+	}
+	if answerText == "" {
+		s.failed(w, v, q, s.core.T(v.lang, "web.error.empty_answer"))
+		return
+	}
+
+	s.done(w, r, v, q, "answer", s.core.AnswerQuestion(v.ctx, q, answerText))
+}
+
+func (s *Server) reject(w http.ResponseWriter, r *http.Request, v visit) {
+	q, ok := s.loadQuestion(w, r, v)
+	if !ok {
+		return
+	}
+	s.done(w, r, v, q, "reject", s.core.RejectQuestion(v.ctx, q, r.PostFormValue("reason")))
+}
+
+func (s *Server) tags(w http.ResponseWriter, r *http.Request, v visit) {
+	q, ok := s.loadQuestion(w, r, v)
+	if !ok {
+		return
+	}
+	s.done(w, r, v, q, "tags", s.core.TagQuestion(v.ctx, q, core.ParseTags(r.PostFormValue("tags"))))
+}
+
+// done завершает действие action с вопросом: при успехе пишет его в журнал и возвращает
+// к вопросу (POST/redirect/GET), при ошибке показывает её на странице вопроса.
+func (s *Server) done(w http.ResponseWriter, r *http.Request, v visit, q *models.Question, action string, err error) {
+	switch {
+	case errors.Is(err, core.ErrAlreadyAnswered):
+		s.failed(w, v, q, s.core.T(v.lang, "common.already_answered"))
+	case errors.Is(err, core.ErrRejected):
+		s.failed(w, v, q, s.core.T(v.lang, "common.already_rejected"))
+	case err != nil:
+		slog.ErrorContext(v.ctx, "Ошибка действия в веб-панели", "action", action, "question", q.ID, "error", err)
+		s.failed(w, v, q, s.core.T(v.lang, "common.update_failed", i18n.Params{"error": err}))
+	default:
+		slog.InfoContext(v.ctx, "Действие в веб-панели", "action", action, "question", q.ID)
+		http.Redirect(w, r, fmt.Sprintf("/questions/%d?done=%s", q.ID, action), http.StatusSeeOther)
+	}
+}
+
+// failed показывает страницу вопроса с ошибкой действия.
+func (s *Server) failed(w http.ResponseWriter, v visit, q *models.Question, message string) {
+	p := v.page()
+	p.Error = message
+	s.showQuestion(w, http.StatusConflict, v, q, p)
+}
+
+// statusKey возвращает ключ i18n состояния вопроса.
+func statusKey(q *models.Question) string {
+	switch {
+	case q.Answered:
+		return "web.status.answered"
+	case q.Rejected:
+		return "web.status.rejected"
+	case q.Held:
+		return "web.status.held"
+	}
+	return "web.status.open"
+}
@@ -0,0 +1,304 @@
+package web_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"telegram-anonymous-bot/internal/bot/core"
+	"telegram-anonymous-bot/internal/bot/telegramtest"
+	"telegram-anonymous-bot/internal/config"
+	"telegram-anonymous-bot/internal/models"
+	"telegram-anonymous-bot/internal/storage"
+	"telegram-anonymous-bot/internal/web"
+)
+
+const (
+	token = "fake_token"
+	admin = 999999
+	user  = 12345
+)
+
+type panel struct {
+	*web.Server
+	url   string
+	tg    *telegramtest.Server
+	store *storage.SQLiteStorage
+	cfg   *config.Config
+}
+
+// newPanel поднимает панель поверх SQLite и telegramtest.Server.
+func newPanel(t *testing.T) *panel {
+	t.Helper()
+	tg := telegramtest.NewServer()
+	t.Cleanup(tg.Close)
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "bot.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(token, tg.Endpoint())
+	if err != nil {
+		t.Fatalf("NewBotAPI failed: %v", err)
+	}
+
+	cfg := &config.Config{TelegramBotToken: token, TelegramAPIEndpoint: tg.Endpoint(), AdminID: admin}
+	server := web.New(&core.BotCore{Messenger: api, Config: cfg, Storage: store}, "test_bot")
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	cfg.WebURL = ts.URL
+	return &panel{Server: server, url: ts.URL, tg: tg, store: store, cfg: cfg}
+}
+
+func newClient() *http.Client {
+	jar, _ := cookiejar.New(nil)
+	return &http.Client{Jar: jar}
+}
+
+func get(t *testing.T, client *http.Client, target string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(target)
+	return read(t, resp, err)
+}
+
+func post(t *testing.T, client *http.Client, target string, form url.Values) (int, string) {
+	t.Helper()
+	resp, err := client.PostForm(target, form)
+	return read(t, resp, err)
+}
+
+func read(t *testing.T, resp *http.Response, err error) (int, string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// login входит в панель по одноразовой ссылке, как после команды /web.
+func (p *panel) login(t *testing.T, userID int64) (*http.Client, int, string) {
+	t.Helper()
+	link, err := p.LoginLink(userID)
+	if err != nil {
+		t.Fatalf("LoginLink failed: %v", err)
+	}
+	client := newClient()
+	if status, body := get(t, client, link); status != http.StatusOK || !strings.Contains(body, `action="/login/link"`) {
+		t.Fatalf("Expected a confirmation page, got %d:\n%s", status, body)
+	}
+	u, _ := url.Parse(link)
+	status, body := post(t, client, p.url+"/login/link", url.Values{"token": {u.Query().Get("token")}})
+	return client, status, body
+}
+
+var csrfPattern = regexp.MustCompile(`name="csrf" value="([^"]+)"`)
+
+func csrf(t *testing.T, body string) string {
+	t.Helper()
+	m := csrfPattern.FindStringSubmatch(body)
+	if m == nil {
+		t.Fatalf("No CSRF token in page:\n%s", body)
+	}
+	return m[1]
+}
+
+func TestLoginLink(t *testing.T) {
+	p := newPanel(t)
+
+	if status, body := get(t, newClient(), p.url+"/"); status != http.StatusOK || !strings.Contains(body, "data-telegram-login=\"test_bot\"") {
+		t.Errorf("Expected a redirect to the login page with the widget, got %d:\n%s", status, body)
+	}
+
+	client, status, body := p.login(t, admin)
+	if status != http.StatusOK || !strings.Contains(body, `action="/logout"`) {
+		t.Fatalf("Expected the question list after login, got %d:\n%s", status, body)
+	}
+
+	// Ссылка одноразовая, а не администратор войти не может
+	link, _ := p.LoginLink(admin)
+	u, _ := url.Parse(link)
+	form := url.Values{"token": {u.Query().Get("token")}}
+	post(t, newClient(), p.url+"/login/link", form)
+	if status, _ := post(t, newClient(), p.url+"/login/link", form); status != http.StatusForbidden {
+		t.Errorf("Expected a used link to be rejected, got %d", status)
+	}
+	if _, status, body := p.login(t, user); status != http.StatusForbidden || !strings.Contains(body, "только администраторам") {
+		t.Errorf("Expected non-admins to be denied, got %d:\n%s", status, body)
+	}
+
+	// Выход требует CSRF-токен
+	if status, _ := post(t, client, p.url+"/logout", nil); status != http.StatusForbidden {
+		t.Errorf("Expected logout without CSRF token to fail, got %d", status)
+	}
+	post(t, client, p.url+"/logout", url.Values{"csrf": {csrf(t, body)}})
+	if status, body := get(t, client, p.url+"/"); status != http.StatusOK || strings.Contains(body, `action="/logout"`) {
+		t.Errorf("Expected the login page after logout, got %d", status)
+	}
+}
+
+// copyClient возвращает новый клиент с cookie client — как украденная или
+// оставленная в другом браузере сессия.
+func (p *panel) copyClient(t *testing.T, client *http.Client) *http.Client {
+	t.Helper()
+	u, _ := url.Parse(p.url)
+	other := newClient()
+	other.Jar.SetCookies(u, client.Jar.Cookies(u))
+	return other
+}
+
+func TestSessionRevocation(t *testing.T) {
+	p := newPanel(t)
+	loggedIn := func(client *http.Client) bool {
+		_, body := get(t, client, p.url+"/")
+		return strings.Contains(body, `action="/logout"`)
+	}
+
+	// Выход закрывает и копии cookie, но не мешает войти снова
+	client, _, body := p.login(t, admin)
+	copied := p.copyClient(t, client)
+	post(t, client, p.url+"/logout", url.Values{"csrf": {csrf(t, body)}})
+	if loggedIn(copied) {
+		t.Error("Expected a copied session cookie to stop working after logout")
+	}
+	if client, _, _ := p.login(t, admin); !loggedIn(client) {
+		t.Error("Expected a fresh login to work after logout")
+	}
+
+	// Отзыв прав закрывает сессию; вернувшиеся права её не оживляют
+	p.cfg.AdminIDs = []int{user}
+	client, _, _ = p.login(t, user)
+	p.cfg.AdminIDs = nil
+	if loggedIn(client) {
+		t.Error("Expected the session to end when admin rights are revoked")
+	}
+	p.cfg.AdminIDs = []int{user}
+	if loggedIn(client) {
+		t.Error("Expected a revoked session to stay closed after rights are restored")
+	}
+}
+
+// widgetQuery подписывает данные Login Widget, как это делает Telegram.
+func widgetQuery(botToken string, values url.Values) string {
+	var fields []string
+	for key := range values {
+		fields = append(fields, key+"="+values.Get(key))
+	}
+	sort.Strings(fields)
+	secret := sha256.Sum256([]byte(botToken))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(fields, "\n")))
+	values.Set("hash", hex.EncodeToString(mac.Sum(nil)))
+	return values.Encode()
+}
+
+func TestLoginWidget(t *testing.T) {
+	p := newPanel(t)
+	now := fmt.Sprint(time.Now().Unix())
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"admin", widgetQuery(token, url.Values{"id": {fmt.Sprint(admin)}, "first_name": {"Админ"}, "auth_date": {now}}), http.StatusOK},
+		{"not admin", widgetQuery(token, url.Values{"id": {fmt.Sprint(user)}, "auth_date": {now}}), http.StatusForbidden},
+		{"other bot", widgetQuery("other_token", url.Values{"id": {fmt.Sprint(admin)}, "auth_date": {now}}), http.StatusForbidden},
+		{"outdated", widgetQuery(token, url.Values{"id": {fmt.Sprint(admin)}, "auth_date": {fmt.Sprint(time.Now().Add(-time.Hour).Unix())}}), http.StatusForbidden},
+		{"unsigned", url.Values{"id": {fmt.Sprint(admin)}, "auth_date": {now}}.Encode(), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		status, body := get(t, newClient(), p.url+"/login/telegram?"+tt.query)
+		if status != tt.status {
+			t.Errorf("%s: expected %d, got %d:\n%s", tt.name, tt.status, status, body)
+		}
+	}
+}
+
+func TestQuestionActions(t *testing.T) {
+	p := newPanel(t)
+	questions := []*models.Question{
+		{UserID: user, Username: "u", Text: "Когда начнётся сессия?", Tags: []string{"учёба"}},
+		{UserID: user, Username: "u", Text: "Где столовая?"},
+		{UserID: user, Username: "u", Text: "Смотрите фото", FileID: "photo-1", MediaType: "photo", Category: "spam"},
+	}
+	for _, q := range questions {
+		if err := p.store.SaveQuestion(q); err != nil {
+			t.Fatalf("SaveQuestion failed: %v", err)
+		}
+	}
+	questions[1].Answered, questions[1].Answer = true, "На первом этаже."
+	if err := p.store.UpdateQuestion(questions[1]); err != nil {
+		t.Fatalf("UpdateQuestion failed: %v", err)
+	}
+	jpeg := []byte("\xff\xd8\xff\xe0 fake jpeg")
+	p.tg.AddFile("photo-1", jpeg)
+
+	client, _, _ := p.login(t, admin)
+	filters := []struct {
+		query string
+		shown []int
+	}{
+		{"", []int{1, 3}},
+		{"?status=answered", []int{2}},
+		{"?" + url.Values{"status": {"all"}, "q": {"СЕССИЯ"}}.Encode(), []int{1}},
+		{"?" + url.Values{"status": {"all"}, "tag": {"#учёба"}}.Encode(), []int{1}},
+		{"?category=spam", []int{3}},
+	}
+	for _, f := range filters {
+		_, body := get(t, client, p.url+"/"+f.query)
+		for id := 1; id <= len(questions); id++ {
+			shown := strings.Contains(body, fmt.Sprintf(`href="/questions/%d"`, id))
+			if shown != slices.Contains(f.shown, id) {
+				t.Errorf("Filter %q: question %d shown=%v, expected %v", f.query, id, shown, !shown)
+			}
+		}
+	}
+
+	_, page := get(t, client, p.url+"/questions/1")
+	token := csrf(t, page)
+	if status, _ := post(t, client, p.url+"/questions/1/answer", url.Values{"answer": {"В январе."}}); status != http.StatusForbidden {
+		t.Errorf("Expected an action without CSRF token to be forbidden, got %d", status)
+	}
+	if status, body := post(t, client, p.url+"/questions/1/answer", url.Values{"csrf": {token}, "answer": {"В январе."}}); status != http.StatusOK || !strings.Contains(body, "Ответ отправлен") {
+		t.Errorf("Expected the answer to be sent, got %d:\n%s", status, body)
+	}
+	if status, body := post(t, client, p.url+"/questions/1/answer", url.Values{"csrf": {token}, "answer": {"Ещё раз"}}); status != http.StatusConflict || !strings.Contains(body, "уже был дан ответ") {
+		t.Errorf("Expected a second answer to be refused, got %d:\n%s", status, body)
+	}
+
+	post(t, client, p.url+"/questions/3/reject", url.Values{"csrf": {token}, "reason": {"Реклама"}})
+	post(t, client, p.url+"/questions/3/tags", url.Values{"csrf": {token}, "tags": {"#Спам, фото"}})
+	got, _ := p.store.GetQuestion(3)
+	if !got.Rejected || got.Answer != "Реклама" || strings.Join(got.Tags, ",") != "спам,фото" {
+		t.Errorf("Unexpected question after reject and tags: %+v", got)
+	}
+	if msgs := p.tg.Messages(user); len(msgs) != 2 || msgs[0] != "Ответ на ваш вопрос (ID=1):\nВ январе." || !strings.Contains(msgs[1], "Причина: Реклама") {
+		t.Errorf("Expected the answer and the rejection to reach the author, got %v", msgs)
+	}
+
+	resp, err := client.Get(p.url + "/questions/3/media")
+	status, body := read(t, resp, err)
+	if status != http.StatusOK || body != string(jpeg) || resp.Header.Get("Content-Type") != "image/jpeg" {
+		t.Errorf("Expected the photo to be proxied, got %d %q %q", status, resp.Header.Get("Content-Type"), body)
+	}
+	if _, body := get(t, newClient(), p.url+"/questions/3/media"); body == string(jpeg) || len(p.tg.Calls("getFile")) != 1 {
+		t.Errorf("Expected media to require login, got %d getFile calls", len(p.tg.Calls("getFile")))
+	}
+}
@@ -1,16 +1,116 @@
+// Package logger настраивает журнал приложения на log/slog: уровень, формат (text или json)
+// и атрибуты обновления (ID обновления, чат, команда), которые With кладёт в context
+// и которые попадают в каждую строку, записанную с этим context.
 package logger
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 )
 
+// Init включает журнал по умолчанию: текст уровня info в stderr. Вызывается до загрузки
+// конфигурации; Setup затем применяет LOG_LEVEL и LOG_FORMAT.
+func Init() {
+	_ = Setup(os.Stderr, "info", "text")
+}
+
+// Setup настраивает журнал: level — debug, info, warn или error; format — text или json.
+// Стандартный пакет log тоже пишет через slog (уровень info).
+func Setup(out io.Writer, level, format string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("некорректный LOG_LEVEL %q: %w", level, err)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "json":
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		return fmt.Errorf("некорректный LOG_FORMAT %q: ожидается text или json", format)
+	}
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	log.SetFlags(0)
+	return nil
+}
+
+type attrsKey struct{}
+
+// With возвращает context, в каждую строку журнала из которого добавляются args
+// (пары ключ-значение, как в slog.With).
+func With(ctx context.Context, args ...any) context.Context {
+	attrs := append(attrsFrom(ctx), slog.Group("", args...).Value.Group()...)
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs[:len(attrs):len(attrs)]
+}
+
+// contextHandler добавляет к записи атрибуты, сохранённые в context через With.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		r.AddAttrs(attrsFrom(ctx)...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
 var (
-	InfoLogger  *log.Logger
-	ErrorLogger *log.Logger
+	saltMu sync.RWMutex
+	salt   []byte
 )
 
-func Init() {
-	InfoLogger = log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
-	ErrorLogger = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+// SetSalt задаёт ключ, с которым хешируются ID пользователей и чатов (LOG_ID_SALT).
+// Пустой ключ — случайный: хеши совпадают только в пределах одного запуска.
+func SetSalt(s string) {
+	saltMu.Lock()
+	defer saltMu.Unlock()
+	salt = []byte(s)
 }
+
+// ID возвращает хеш Telegram ID для журнала: по нему можно связать строки одного
+// пользователя, но нельзя узнать, кто он, — бот анонимный.
+func ID(id int64) string {
+	saltMu.RLock()
+	key := salt
+	saltMu.RUnlock()
+	if len(key) == 0 {
+		key = randomSalt()
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strconv.FormatInt(id, 10)))
+	return hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+var randomSalt = sync.OnceValue(func() []byte {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return b
+})
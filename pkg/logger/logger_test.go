@@ -0,0 +1,75 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"telegram-anonymous-bot/pkg/logger"
+)
+
+func TestSetupJSONWithContext(t *testing.T) {
+	var out bytes.Buffer
+	if err := logger.Setup(&out, "info", "json"); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	defer logger.Init()
+
+	ctx := logger.With(context.Background(), "update_id", 7, "chat", "abc")
+	ctx = logger.With(ctx, "command", "ask")
+	slog.DebugContext(ctx, "скрыто")
+	slog.InfoContext(ctx, "обработано", "duration", "1ms")
+	slog.Info("без контекста")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines above debug level, got %q", out.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected JSON line, got %q: %v", lines[0], err)
+	}
+	want := map[string]any{"msg": "обработано", "update_id": 7.0, "chat": "abc", "command": "ask", "duration": "1ms"}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+	if strings.Contains(lines[1], "update_id") {
+		t.Errorf("Expected no update attributes without context, got %q", lines[1])
+	}
+}
+
+func TestSetupInvalid(t *testing.T) {
+	var out bytes.Buffer
+	if err := logger.Setup(&out, "verbose", "text"); err == nil {
+		t.Errorf("Expected an error for unknown level")
+	}
+	if err := logger.Setup(&out, "info", "xml"); err == nil {
+		t.Errorf("Expected an error for unknown format")
+	}
+}
+
+func TestID(t *testing.T) {
+	logger.SetSalt("salt")
+	defer logger.SetSalt("")
+
+	id := logger.ID(12345)
+	if id != logger.ID(12345) {
+		t.Errorf("Expected a stable hash for the same ID")
+	}
+	if id == logger.ID(12346) {
+		t.Errorf("Expected different IDs to hash differently")
+	}
+	if strings.Contains(id, "12345") || len(id) != 12 {
+		t.Errorf("Expected a 12-char hash without the ID, got %q", id)
+	}
+
+	logger.SetSalt("other")
+	if logger.ID(12345) == id {
+		t.Errorf("Expected the hash to depend on the salt")
+	}
+}